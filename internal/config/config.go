@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -14,12 +15,14 @@ type Config struct {
 	Telemetry   TelemetryConfig   `mapstructure:"telemetry"`
 	Application ApplicationConfig `mapstructure:"application"`
 	Apm         Apm               `mapstructure:"apm"`
+	Events      EventsConfig      `mapstructure:"events"`
 }
 
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
-	Mode string `mapstructure:"mode"` // debug, release, test
+	Host       string `mapstructure:"host"`
+	Port       int    `mapstructure:"port"`
+	Mode       string `mapstructure:"mode"`        // debug, release, test
+	AdminToken string `mapstructure:"admin_token"` // required by /admin endpoints
 }
 
 type DatabaseConfig struct {
@@ -39,13 +42,111 @@ type PostgreSQLConfig struct {
 type LoggerConfig struct {
 	Level    string `mapstructure:"level"`
 	Format   string `mapstructure:"format"`   // json, console
-	Provider string `mapstructure:"provider"` // stdout, file, elasticsearch
+	Provider string `mapstructure:"provider"` // stdout, file, elasticsearch, logstash, otlp, loki, syslog
 	Index    string `mapstructure:"index"`    // for elasticsearch
-	Url      string `mapstructure:"url"`      // for elasticsearch
+	Url      string `mapstructure:"url"`      // for elasticsearch, logstash, loki and syslog
 	ApiKey   string `mapstructure:"api_key"`  // for elasticsearch
-	Username string `mapstructure:"username"` // for elasticsearch
-	Password string `mapstructure:"password"` // for elasticsearch
+	Username string `mapstructure:"username"` // for elasticsearch and loki
+	Password string `mapstructure:"password"` // for elasticsearch and loki
 	Filepath string `mapstructure:"filepath"` // for file logging
+
+	MaxSizeMB      int           `mapstructure:"max_size_mb"`     // for file logging, rotate at this size, 0 disables
+	RotateInterval time.Duration `mapstructure:"rotate_interval"` // for file logging, rotate this often, 0 disables
+	MaxBackups     int           `mapstructure:"max_backups"`     // for file logging, rotated files to keep, 0 keeps all
+	MaxAge         time.Duration `mapstructure:"max_age"`         // for file logging, remove older rotated files, 0 keeps all
+	Compress       bool          `mapstructure:"compress"`        // for file logging, gzip rotated files
+
+	BulkSize       int           `mapstructure:"bulk_size"`        // for elasticsearch, otlp and loki, documents per bulk request
+	BulkFlushBytes int           `mapstructure:"bulk_flush_bytes"` // for elasticsearch and loki, flush once the batch reaches this size
+	FlushInterval  time.Duration `mapstructure:"flush_interval"`   // for elasticsearch, otlp, loki and file logging, flush at least this often
+	QueueSize      int           `mapstructure:"queue_size"`       // for elasticsearch, logstash, otlp, loki and syslog, logs waiting to be sent
+	MaxRetries     int           `mapstructure:"max_retries"`      // for elasticsearch and loki, attempts for rejected documents
+
+	Transport           string        `mapstructure:"transport"`             // for logstash: tcp, tls, udp; for syslog: unix, udp, tcp
+	TLSCAFile           string        `mapstructure:"tls_ca_file"`           // for logstash over tls, CA of the server certificate
+	TLSSkipVerify       bool          `mapstructure:"tls_skip_verify"`       // for logstash over tls, do not verify the server certificate
+	ReconnectMaxBackoff time.Duration `mapstructure:"reconnect_max_backoff"` // for logstash and syslog, longest wait between reconnects
+
+	Labels      map[string]string `mapstructure:"labels"`       // for loki, labels of every stream, defaults to service and environment
+	LabelFields []string          `mapstructure:"label_fields"` // for loki, fields promoted to stream labels; "level" is the log level
+	TenantID    string            `mapstructure:"tenant_id"`    // for loki, sent as X-Scope-OrgID
+	Encoding    string            `mapstructure:"encoding"`     // for loki: protobuf (snappy), json
+
+	Facility string `mapstructure:"facility"` // for syslog: kern, user, daemon, local0..local7...
+	AppName  string `mapstructure:"app_name"` // for syslog, APP-NAME of every message, defaults to the application name
+
+	Redaction RedactionConfig `mapstructure:"redaction"`
+	Sampling  SamplingConfig  `mapstructure:"sampling"` // per provider, sinks without their own inherit it
+
+	IncludeFields []string       `mapstructure:"include_fields"` // only ship these fields, empty ships all
+	ExcludeFields []string       `mapstructure:"exclude_fields"` // never ship these fields
+	Sinks         []LoggerConfig `mapstructure:"sinks"`          // fan out to several providers; unset options fall back to the ones above
+
+	// SetKeys holds the keys a sink sets itself, so that zero values such as
+	// compress: false override the top-level options. Load fills it; sinks
+	// built without it override with their non-zero options only.
+	SetKeys map[string]bool `mapstructure:"-"`
+}
+
+// UsesProvider reports whether provider receives logs, either as the
+// provider or as the provider of a sink
+func (c LoggerConfig) UsesProvider(provider string) bool {
+	if len(c.Sinks) == 0 {
+		return c.Provider == provider
+	}
+	for _, sink := range c.Sinks {
+		if sink.Provider == provider || (sink.Provider == "" && c.Provider == provider) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactionConfig controls how personal data is removed from logs before
+// they reach any provider
+type RedactionConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Mode     string   `mapstructure:"mode"`      // mask, hash
+	HashSalt string   `mapstructure:"hash_salt"` // for hash mode
+	Keys     []string `mapstructure:"keys"`      // field key patterns (regular expressions, case-insensitive)
+	Patterns []string `mapstructure:"patterns"`  // extra regular expressions masked in messages and values
+	Allow    []string `mapstructure:"allow"`     // field keys never redacted
+}
+
+// SamplingConfig limits how many logs with the same level and message a
+// provider ships. Every configured rule must let a log through.
+type SamplingConfig struct {
+	Enabled       bool               `mapstructure:"enabled" json:"enabled"`
+	Rates         map[string]float64 `mapstructure:"rates" json:"rates,omitempty"`                     // fraction of logs kept per level, e.g. debug: 0.1
+	First         int                `mapstructure:"first" json:"first,omitempty"`                     // logs of a message kept per period
+	Thereafter    int                `mapstructure:"thereafter" json:"thereafter,omitempty"`           // then keep every Nth, 0 drops the rest
+	Period        time.Duration      `mapstructure:"period" json:"period,omitempty"`                   // window of first and thereafter
+	RatePerSecond float64            `mapstructure:"rate_per_second" json:"rate_per_second,omitempty"` // token bucket refill per message
+	Burst         int                `mapstructure:"burst" json:"burst,omitempty"`                     // token bucket size per message
+}
+
+type EventsConfig struct {
+	Adapter              string               `mapstructure:"adapter"`                // memory, nats
+	BufferSize           int                  `mapstructure:"buffer_size"`            // default channel subscriber buffer
+	HandlerTimeout       time.Duration        `mapstructure:"handler_timeout"`        // default handler deadline, 0 disables
+	SlowHandlerThreshold time.Duration        `mapstructure:"slow_handler_threshold"` // warn about slower handlers, 0 disables
+	NATS                 NATSConfig           `mapstructure:"nats"`
+	Postgres             PostgresBridgeConfig `mapstructure:"postgres"`
+}
+
+type NATSConfig struct {
+	URL           string `mapstructure:"url"`
+	SubjectPrefix string `mapstructure:"subject_prefix"`
+	QueueGroup    string `mapstructure:"queue_group"`
+	JetStream     bool   `mapstructure:"jetstream"`
+	Stream        string `mapstructure:"stream"`  // for jetstream
+	Durable       string `mapstructure:"durable"` // for jetstream
+}
+
+type PostgresBridgeConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Channel string   `mapstructure:"channel"`
+	Topics  []string `mapstructure:"topics"` // topics forwarded between instances
 }
 
 type TelemetryConfig struct {
@@ -101,15 +202,44 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
+	markSinkKeys(&cfg.Logger, viper.Get("logger.sinks"))
 
 	return &cfg, nil
 }
 
+// markSinkKeys records the keys every configured sink sets
+func markSinkKeys(cfg *LoggerConfig, raw interface{}) {
+	sinks, ok := raw.([]interface{})
+	if !ok {
+		return
+	}
+
+	for i, sink := range sinks {
+		if i >= len(cfg.Sinks) {
+			return
+		}
+
+		keys := make(map[string]bool)
+		switch m := sink.(type) {
+		case map[string]interface{}:
+			for k := range m {
+				keys[strings.ToLower(k)] = true
+			}
+		case map[interface{}]interface{}:
+			for k := range m {
+				keys[strings.ToLower(fmt.Sprint(k))] = true
+			}
+		}
+		cfg.Sinks[i].SetKeys = keys
+	}
+}
+
 func setDefaults() {
 	// Server defaults
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.admin_token", "")
 
 	// Database defaults
 	viper.SetDefault("database.driver", "sqlite")
@@ -127,10 +257,44 @@ func setDefaults() {
 	viper.SetDefault("logger.provider", "stdout")
 	viper.SetDefault("logger.filepath", "./logs/app.log")
 	viper.SetDefault("logger.index", "boilerplate-go-logs")
-	viper.SetDefault("logger.url", "http://localhost:9200")
+	// Each provider has its own address and transport defaults
+	viper.SetDefault("logger.url", "")
 	viper.SetDefault("logger.username", "")
 	viper.SetDefault("logger.password", "")
 	viper.SetDefault("logger.api_key", "")
+	viper.SetDefault("logger.max_size_mb", 100)
+	viper.SetDefault("logger.rotate_interval", 24*time.Hour)
+	viper.SetDefault("logger.max_backups", 7)
+	viper.SetDefault("logger.max_age", 30*24*time.Hour)
+	viper.SetDefault("logger.compress", true)
+	viper.SetDefault("logger.transport", "")
+	viper.SetDefault("logger.reconnect_max_backoff", 30*time.Second)
+	viper.SetDefault("logger.label_fields", []string{"level"})
+	viper.SetDefault("logger.encoding", "protobuf")
+	viper.SetDefault("logger.redaction.enabled", true)
+	viper.SetDefault("logger.redaction.mode", "mask")
+	viper.SetDefault("logger.sampling.enabled", false)
+	viper.SetDefault("logger.sampling.period", time.Second)
+	viper.SetDefault("logger.bulk_size", 500)
+	viper.SetDefault("logger.bulk_flush_bytes", 5<<20)
+	viper.SetDefault("logger.flush_interval", 5*time.Second)
+	viper.SetDefault("logger.queue_size", 10000)
+	viper.SetDefault("logger.max_retries", 3)
+
+	// Events defaults
+	viper.SetDefault("events.adapter", "memory")
+	viper.SetDefault("events.buffer_size", 100)
+	viper.SetDefault("events.handler_timeout", 30*time.Second)
+	viper.SetDefault("events.slow_handler_threshold", 5*time.Second)
+	viper.SetDefault("events.nats.url", "nats://localhost:4222")
+	viper.SetDefault("events.nats.subject_prefix", "events")
+	viper.SetDefault("events.nats.queue_group", "")
+	viper.SetDefault("events.nats.jetstream", false)
+	viper.SetDefault("events.nats.stream", "EVENTS")
+	viper.SetDefault("events.nats.durable", "")
+	viper.SetDefault("events.postgres.enabled", false)
+	viper.SetDefault("events.postgres.channel", "events")
+	viper.SetDefault("events.postgres.topics", []string{})
 
 	// Telemetry defaults
 	viper.SetDefault("telemetry.enabled", false)
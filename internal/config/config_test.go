@@ -2,8 +2,11 @@ package config_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/your-org/boilerplate-go/internal/config"
 )
 
@@ -26,6 +29,10 @@ func TestLoad(t *testing.T) {
 	if cfg.Database.Driver != "sqlite" {
 		t.Errorf("Expected default driver 'sqlite', got %s", cfg.Database.Driver)
 	}
+
+	if cfg.Events.HandlerTimeout != 30*time.Second {
+		t.Errorf("Expected default handler timeout 30s, got %s", cfg.Events.HandlerTimeout)
+	}
 }
 
 func TestLoadWithEnvVars(t *testing.T) {
@@ -42,3 +49,99 @@ func TestLoadWithEnvVars(t *testing.T) {
 		t.Errorf("Expected port from env var 9090, got %d", cfg.Server.Port)
 	}
 }
+
+func TestLoadEventDurationsFromEnv(t *testing.T) {
+	os.Setenv("APP_EVENTS_HANDLER_TIMEOUT", "2s")
+	defer os.Unsetenv("APP_EVENTS_HANDLER_TIMEOUT")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cfg.Events.HandlerTimeout != 2*time.Second {
+		t.Errorf("Expected handler timeout from env var 2s, got %s", cfg.Events.HandlerTimeout)
+	}
+}
+
+// loadFile loads the configuration from a config.yaml holding content
+func loadFile(t *testing.T, content string) *config.Config {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		viper.Reset()
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return cfg
+}
+
+func TestLoadSinkKeys(t *testing.T) {
+	cfg := loadFile(t, `
+logger:
+  compress: true
+  max_retries: 3
+  sinks:
+    - provider: file
+      compress: false
+      max_retries: 0
+    - provider: stdout
+`)
+
+	if len(cfg.Logger.Sinks) != 2 {
+		t.Fatalf("Expected 2 sinks, got %d", len(cfg.Logger.Sinks))
+	}
+	first := cfg.Logger.Sinks[0].SetKeys
+	if !first["provider"] || !first["compress"] || !first["max_retries"] || first["level"] {
+		t.Errorf("Expected the keys set by the first sink, got %v", first)
+	}
+	if second := cfg.Logger.Sinks[1].SetKeys; len(second) != 1 || !second["provider"] {
+		t.Errorf("Expected only the provider set by the second sink, got %v", second)
+	}
+}
+
+func TestLoggerConfigUsesProvider(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  config.LoggerConfig
+		want bool
+	}{
+		{"provider", config.LoggerConfig{Provider: "otlp"}, true},
+		{"other provider", config.LoggerConfig{Provider: "stdout"}, false},
+		{"sink", config.LoggerConfig{Provider: "stdout", Sinks: []config.LoggerConfig{{Provider: "file"}, {Provider: "otlp"}}}, true},
+		{"sink inheriting the provider", config.LoggerConfig{Provider: "otlp", Sinks: []config.LoggerConfig{{}}}, true},
+		{"sinks replace the provider", config.LoggerConfig{Provider: "otlp", Sinks: []config.LoggerConfig{{Provider: "stdout"}}}, false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.cfg.UsesProvider("otlp"); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestLoadSyslogDefaults(t *testing.T) {
+	cfg := loadFile(t, `
+logger:
+  provider: syslog
+`)
+
+	if cfg.Logger.Url != "" || cfg.Logger.Transport != "" {
+		t.Errorf("Expected no shared url or transport, got %q over %q", cfg.Logger.Url, cfg.Logger.Transport)
+	}
+}
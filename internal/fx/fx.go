@@ -2,6 +2,10 @@ package fx
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
 
 	"go.uber.org/fx"
 
@@ -12,8 +16,10 @@ import (
 	"github.com/your-org/boilerplate-go/internal/server"
 	"github.com/your-org/boilerplate-go/internal/telemetry"
 	"github.com/your-org/boilerplate-go/internal/user/application"
+	"github.com/your-org/boilerplate-go/internal/user/domain"
 	"github.com/your-org/boilerplate-go/internal/user/infrastructure"
 	"github.com/your-org/boilerplate-go/internal/user/presentation"
+	"github.com/your-org/boilerplate-go/pkg/events"
 	"gorm.io/gorm"
 )
 
@@ -23,6 +29,7 @@ var AppModule = fx.Module("app",
 	LoggerModule,
 	TelemetryModule,
 	DatabaseModule,
+	EventsModule,
 	UserModule,
 	ServerModule,
 )
@@ -36,6 +43,7 @@ var ConfigModule = fx.Module("config",
 var LoggerModule = fx.Module("logger",
 	fx.Provide(NewLogger),
 	fx.Provide(NewZerologLogger),
+	fx.Invoke(SetDefaultSlog),
 )
 
 // TelemetryModule fornece telemetria
@@ -50,6 +58,12 @@ var DatabaseModule = fx.Module("database",
 	fx.Invoke(SetupTracing),
 )
 
+// EventsModule fornece o barramento de eventos
+var EventsModule = fx.Module("events",
+	fx.Provide(NewEventBus),
+	fx.Invoke(StartPostgresBridge),
+)
+
 // UserModule fornece componentes do domínio User
 var UserModule = fx.Module("user",
 	fx.Provide(infrastructure.NewGormUserRepository),
@@ -62,20 +76,162 @@ var ServerModule = fx.Module("server",
 	fx.Provide(server.New),
 )
 
-// NewLogger adapter para o logger
-func NewLogger(cfg *config.Config) *logger.Logger {
-	appLogger := logger.InitLogger(cfg.Logger)
+// NewLogger adapter para o logger, descarregando os logs pendentes no encerramento.
+// O provider otlp usa o mesmo endpoint e resource da telemetria.
+func NewLogger(lc fx.Lifecycle, cfg *config.Config) *logger.Logger {
+	var opts []logger.Option
+	if cfg.Logger.UsesProvider("otlp") {
+		// Só o provider otlp depende do exporter e do resource
+		telemetry.ConfigureExporter(cfg)
+		res, err := telemetry.NewResource(context.Background(), cfg)
+		if err != nil {
+			// O resource parcial continua utilizável
+			fmt.Printf("Failed to detect every telemetry resource attribute: %v\n", err)
+		}
+		opts = append(opts, logger.WithResource(res))
+	}
+
+	loggerCfg := cfg.Logger
+	if loggerCfg.Labels == nil {
+		// Streams do Loki identificados pelo serviço e ambiente
+		loggerCfg.Labels = map[string]string{
+			"service":     cfg.Application.Name,
+			"environment": cfg.Application.Environment,
+		}
+	}
+
+	if loggerCfg.AppName == "" {
+		// APP-NAME das mensagens syslog
+		loggerCfg.AppName = cfg.Application.Name
+	}
+
+	appLogger := logger.InitLogger(loggerCfg, opts...)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return appLogger.Close(ctx)
+		},
+	})
+
 	return &appLogger
 }
 
+// SetDefaultSlog faz as bibliotecas que usam log/slog passarem pelos mesmos
+// providers enquanto a aplicação roda e restaura os loggers padrão anteriores
+// no encerramento
+func SetDefaultSlog(lc fx.Lifecycle, appLogger *logger.Logger) {
+	var (
+		previous *slog.Logger
+		writer   io.Writer
+		flags    int
+	)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			previous, writer, flags = slog.Default(), log.Writer(), log.Flags()
+			slog.SetDefault(appLogger.Slog())
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// slog.SetDefault também redireciona o pacote log
+			slog.SetDefault(previous)
+			log.SetOutput(writer)
+			log.SetFlags(flags)
+			return nil
+		},
+	})
+}
+
 // NewZerologLogger extrai o zerolog.Logger do nosso wrapper
 func NewZerologLogger(log *logger.Logger) zerolog.Logger {
 	return log.Logger
 }
 
 // NewUserService adapter para o service de usuário
-func NewUserService(userRepo *infrastructure.GormUserRepository, log *logger.Logger) *application.UserService {
-	return application.NewUserService(userRepo, log)
+func NewUserService(userRepo *infrastructure.GormUserRepository, eventBus events.EventBus, log *logger.Logger) *application.UserService {
+	return application.NewUserService(userRepo, eventBus, log)
+}
+
+// NewEventBus adapter para o barramento de eventos
+func NewEventBus(lc fx.Lifecycle, cfg *config.Config, appLogger *logger.Logger) (events.EventBus, error) {
+	var eventBus events.EventBus
+
+	busConfig := &events.EventBusConfig{
+		DefaultBufferSize:    cfg.Events.BufferSize,
+		DefaultTimeout:       cfg.Events.HandlerTimeout,
+		SlowHandlerThreshold: cfg.Events.SlowHandlerThreshold,
+		Logger:               appLogger,
+	}
+
+	switch cfg.Events.Adapter {
+	case "", "memory":
+		eventBus = events.NewEventBus(busConfig)
+	case "nats":
+		natsBus, err := events.NewNATSEventBus(events.NATSConfig{
+			URL:           cfg.Events.NATS.URL,
+			Name:          cfg.Application.Name,
+			SubjectPrefix: cfg.Events.NATS.SubjectPrefix,
+			QueueGroup:    cfg.Events.NATS.QueueGroup,
+			JetStream:     cfg.Events.NATS.JetStream,
+			Stream:        cfg.Events.NATS.Stream,
+			Durable:       cfg.Events.NATS.Durable,
+		}, busConfig)
+		if err != nil {
+			return nil, err
+		}
+		registerUserEventTypes(natsBus)
+		eventBus = natsBus
+	default:
+		return nil, fmt.Errorf("unsupported events adapter: %s", cfg.Events.Adapter)
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return eventBus.Close()
+		},
+	})
+
+	return eventBus, nil
+}
+
+// StartPostgresBridge inicia a ponte LISTEN/NOTIFY do Postgres quando habilitada
+func StartPostgresBridge(lc fx.Lifecycle, cfg *config.Config, db *gorm.DB, eventBus events.EventBus) error {
+	if !cfg.Events.Postgres.Enabled {
+		return nil
+	}
+
+	bridge, err := events.NewPostgresBridge(db, eventBus, events.PostgresBridgeConfig{
+		Channel: cfg.Events.Postgres.Channel,
+		Topics:  cfg.Events.Postgres.Topics,
+	})
+	if err != nil {
+		return err
+	}
+	registerUserEventTypes(bridge)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return bridge.Start(context.Background())
+		},
+		OnStop: func(ctx context.Context) error {
+			bridge.Stop()
+			return nil
+		},
+	})
+
+	return nil
+}
+
+// eventTypeRegistry é implementado pelos transportes que decodificam eventos remotos
+type eventTypeRegistry interface {
+	RegisterEventType(topic string, factory events.EventFactory)
+}
+
+// registerUserEventTypes registra os eventos de usuário para decodificação remota
+func registerUserEventTypes(bus eventTypeRegistry) {
+	bus.RegisterEventType(domain.UserCreatedTopic, func() events.Event { return &domain.UserCreatedEvent{} })
+	bus.RegisterEventType(domain.UserUpdatedTopic, func() events.Event { return &domain.UserUpdatedEvent{} })
+	bus.RegisterEventType(domain.UserDeletedTopic, func() events.Event { return &domain.UserDeletedEvent{} })
 }
 
 // NewTelemetryCleanup adapter para telemetria
@@ -1,9 +1,53 @@
 package logger
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // AppLogger interface defines the contract for different logger implementations
 type AppLogger interface {
 	Log(ctx context.Context, level, message string, fields map[string]interface{})
-	AddField(key string, value interface{})
+	// With returns a child logger that adds fields to every log. The receiver
+	// is left untouched, so children can be created and used concurrently.
+	// Children share the connections of their root, which is the only one
+	// to close.
+	With(fields map[string]interface{}) AppLogger
+}
+
+// Closer is implemented by AppLoggers that buffer logs or hold connections
+// and need to flush them on shutdown
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// closeProvider closes appLogger if it holds anything
+func closeProvider(ctx context.Context, appLogger AppLogger) error {
+	switch closer := appLogger.(type) {
+	case Closer:
+		return closer.Close(ctx)
+	case io.Closer:
+		return closer.Close()
+	}
+	return nil
+}
+
+// childLogger binds fields to the logs of a provider. Its fields are never
+// modified after creation.
+type childLogger struct {
+	parent AppLogger
+	fields map[string]interface{}
+}
+
+func newChildLogger(parent AppLogger, fields map[string]interface{}) AppLogger {
+	return &childLogger{parent: parent, fields: mergeFields(nil, fields)}
+}
+
+// Log adds the bound fields; fields given to the call win over them
+func (c *childLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	c.parent.Log(ctx, level, message, mergeFields(c.fields, fields))
+}
+
+func (c *childLogger) With(fields map[string]interface{}) AppLogger {
+	return &childLogger{parent: c.parent, fields: mergeFields(c.fields, fields)}
 }
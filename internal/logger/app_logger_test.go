@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+const (
+	hammerWorkers = 8
+	hammerLogs    = 10
+)
+
+// hammer logs from concurrent children of l, each bound to its own worker.
+// Every message names the worker that logged it.
+func hammer(l AppLogger) {
+	var wg sync.WaitGroup
+	for i := 0; i < hammerWorkers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bound := map[string]interface{}{"worker": i}
+			child := l.With(bound)
+			// Changing the map afterwards must not reach the child
+			bound["worker"] = -1
+
+			for j := 0; j < hammerLogs; j++ {
+				child.With(map[string]interface{}{"step": j}).
+					Log(context.Background(), "info", fmt.Sprintf("w%d", i), map[string]interface{}{"attempt": j})
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestWithDoesNotChangeTheParent(t *testing.T) {
+	recorder := &recordingLogger{}
+	parent := recorder.With(map[string]interface{}{"service": "api"})
+	child := parent.With(map[string]interface{}{"user_id": 1})
+	sibling := parent.With(map[string]interface{}{"user_id": 2, "service": "worker"})
+
+	parent.Log(context.Background(), "info", "parent", nil)
+	child.Log(context.Background(), "info", "child", map[string]interface{}{"user_id": 3})
+	sibling.Log(context.Background(), "info", "sibling", nil)
+
+	got := recorder.logged()
+	if len(got[0].fields) != 1 || got[0].fields["service"] != "api" {
+		t.Errorf("Expected the parent to keep its fields, got %v", got[0].fields)
+	}
+	if got[1].fields["service"] != "api" || got[1].fields["user_id"] != 3 {
+		t.Errorf("Expected call fields to win over bound ones, got %v", got[1].fields)
+	}
+	if got[2].fields["service"] != "worker" || got[2].fields["user_id"] != 2 {
+		t.Errorf("Expected siblings to be independent, got %v", got[2].fields)
+	}
+}
+
+func TestConcurrentWithDoesNotLeakFields(t *testing.T) {
+	recorder := &recordingLogger{}
+	hammer(recorder)
+
+	got := recorder.logged()
+	if len(got) != hammerWorkers*hammerLogs {
+		t.Fatalf("Expected %d logs, got %d", hammerWorkers*hammerLogs, len(got))
+	}
+	for _, entry := range got {
+		if entry.message != fmt.Sprintf("w%d", entry.fields["worker"]) || entry.fields["step"] != entry.fields["attempt"] {
+			t.Fatalf("Expected every log to carry only its own fields, got %+v", entry)
+		}
+	}
+}
+
+func TestLoggerWith(t *testing.T) {
+	recorder := &recordingLogger{}
+	redactor, _ := NewRedactor(config.RedactionConfig{Enabled: true})
+	appLogger := &Logger{AppLogger: recorder, Redactor: redactor}
+
+	child := appLogger.With(map[string]interface{}{"email": "john@example.com", "tenant": "acme"})
+	child.LogInfo(context.Background(), "child")
+	appLogger.LogInfo(context.Background(), "parent")
+
+	got := recorder.logged()
+	if got[0].fields["email"] != redactedValue || got[0].fields["tenant"] != "acme" {
+		t.Errorf("Expected the bound fields to be redacted like any other, got %v", got[0].fields)
+	}
+	if _, ok := got[1].fields["tenant"]; ok {
+		t.Errorf("Expected the parent Logger to be left untouched, got %v", got[1].fields)
+	}
+}
+
+func TestConcurrentLogAndWithStdout(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	stdout := os.Stdout
+	os.Stdout = devNull
+	l := NewStdoutLogger(config.LoggerConfig{Format: "json"})
+	os.Stdout = stdout
+
+	hammer(l)
+}
+
+func TestConcurrentLogAndWithFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l := NewFileLogger(config.LoggerConfig{Filepath: path})
+
+	hammer(l)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(readFile(t, path)), "\n")
+	if len(lines) != hammerWorkers*hammerLogs {
+		t.Fatalf("Expected %d lines, got %d", hammerWorkers*hammerLogs, len(lines))
+	}
+	for _, line := range lines {
+		var entry struct {
+			Message string `json:"message"`
+			Worker  int    `json:"worker"`
+		}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil || entry.Message != fmt.Sprintf("w%d", entry.Worker) {
+			t.Fatalf("Expected every line to carry only its own fields, got %s", line)
+		}
+	}
+}
+
+func TestConcurrentLogAndWithLogstash(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newLogstashServer(t, listener)
+	l := NewLogstashLogger(config.LoggerConfig{Url: listener.Addr().String()})
+
+	hammer(l)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i := 0; i < hammerWorkers*hammerLogs; i++ {
+		<-server.messages
+	}
+}
+
+func TestConcurrentLogAndWithElasticsearch(t *testing.T) {
+	fake, server := newFakeBulk(t, nil)
+	l := newTestElasticsearchLogger(server.URL, config.LoggerConfig{})
+
+	hammer(l)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, indexed := fake.snapshot(); len(indexed) != hammerWorkers*hammerLogs {
+		t.Errorf("Expected %d documents, got %d", hammerWorkers*hammerLogs, len(indexed))
+	}
+}
+
+func TestConcurrentLogAndWithOTLP(t *testing.T) {
+	collector := newFakeCollector(t)
+	l := NewOTLPLogger(config.LoggerConfig{}, nil)
+
+	hammer(l)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if records, _ := collector.records(); len(records) != hammerWorkers*hammerLogs {
+		t.Errorf("Expected %d records, got %d", hammerWorkers*hammerLogs, len(records))
+	}
+}
+
+func TestConcurrentLogAndWithMultiLogger(t *testing.T) {
+	recorder := &recordingLogger{}
+	m := &MultiLogger{sinks: []*sink{newSink("all", recorder, config.LoggerConfig{Level: "debug"})}}
+
+	hammer(m)
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := recorder.logged(); len(got) != hammerWorkers*hammerLogs {
+		t.Errorf("Expected %d logs, got %d", hammerWorkers*hammerLogs, len(got))
+	}
+}
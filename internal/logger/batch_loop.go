@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// batchConfig sizes the batches of a batchLoop
+type batchConfig struct {
+	flushItems    int
+	flushBytes    int
+	flushInterval time.Duration
+	queueSize     int
+}
+
+// batchLoop collects items on its own goroutine and ships them once
+// flushItems or flushBytes is reached, every flushInterval, on flush and on
+// close. Items wait in a bounded queue and are dropped when it is full, so
+// callers never block. The Elasticsearch bulk indexer and the Loki pusher
+// share it and only implement shipping.
+type batchLoop[T any] struct {
+	config batchConfig
+	size   func(T) int
+	// ship sends the pending items, which may be none, on the loop goroutine
+	ship func(pending []T)
+	// finish runs after the last ship once the loop is stopped
+	finish func()
+
+	queue  chan T
+	flushC chan chan struct{}
+	stop   chan struct{}
+	abort  chan struct{}
+	done   chan struct{}
+
+	stopOnce  sync.Once
+	abortOnce sync.Once
+
+	// pending is only touched by the loop goroutine
+	pending      []T
+	pendingBytes int
+
+	dropped atomic.Uint64
+}
+
+func newBatchLoop[T any](cfg batchConfig, size func(T) int, ship func([]T)) *batchLoop[T] {
+	if cfg.flushItems <= 0 {
+		cfg.flushItems = 500
+	}
+	if cfg.flushBytes <= 0 {
+		cfg.flushBytes = 1 << 20
+	}
+	if cfg.flushInterval <= 0 {
+		cfg.flushInterval = 5 * time.Second
+	}
+	if cfg.queueSize <= 0 {
+		cfg.queueSize = 10000
+	}
+
+	return &batchLoop[T]{
+		config: cfg,
+		size:   size,
+		ship:   ship,
+		queue:  make(chan T, cfg.queueSize),
+		flushC: make(chan chan struct{}),
+		stop:   make(chan struct{}),
+		abort:  make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// start runs the loop. Owners call it once their ship and finish are set.
+func (b *batchLoop[T]) start() {
+	go b.run()
+}
+
+// add queues an item without blocking and reports whether it was accepted
+func (b *batchLoop[T]) add(item T) bool {
+	select {
+	case <-b.stop:
+		b.dropped.Add(1)
+		return false
+	default:
+	}
+
+	select {
+	case b.queue <- item:
+		return true
+	default:
+		b.dropped.Add(1)
+		return false
+	}
+}
+
+// flush ships everything queued so far and waits until it is shipped
+func (b *batchLoop[T]) flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case b.flushC <- ack:
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting items and ships the queue. Once ctx is done the
+// retries still waiting are abandoned.
+func (b *batchLoop[T]) close(ctx context.Context) error {
+	b.stopOnce.Do(func() { close(b.stop) })
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		b.abortOnce.Do(func() { close(b.abort) })
+		return ctx.Err()
+	}
+}
+
+// wait sleeps for a retry backoff and reports false if the loop is aborted
+// meanwhile
+func (b *batchLoop[T]) wait(backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-b.abort:
+		return false
+	}
+}
+
+func (b *batchLoop[T]) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.config.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case item := <-b.queue:
+			b.append(item)
+			if len(b.pending) >= b.config.flushItems || b.pendingBytes >= b.config.flushBytes {
+				b.shipPending()
+			}
+		case <-ticker.C:
+			b.shipPending()
+		case ack := <-b.flushC:
+			b.drain()
+			b.shipPending()
+			close(ack)
+		case <-b.stop:
+			b.drain()
+			b.shipPending()
+			if b.finish != nil {
+				b.finish()
+			}
+			return
+		}
+	}
+}
+
+// drain moves every queued item into the pending batch
+func (b *batchLoop[T]) drain() {
+	for {
+		select {
+		case item := <-b.queue:
+			b.append(item)
+		default:
+			return
+		}
+	}
+}
+
+func (b *batchLoop[T]) append(item T) {
+	b.pending = append(b.pending, item)
+	b.pendingBytes += b.size(item)
+}
+
+func (b *batchLoop[T]) shipPending() {
+	pending := b.pending
+	b.pending = nil
+	b.pendingBytes = 0
+	b.ship(pending)
+}
+
+// chunks calls fn with consecutive slices of at most n items
+func chunks[T any](items []T, n int, fn func([]T)) {
+	for len(items) > 0 {
+		size := min(len(items), n)
+		fn(items[:size])
+		items = items[size:]
+	}
+}
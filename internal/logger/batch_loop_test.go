@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// shipped records the batches a batchLoop ships
+type shipped struct {
+	mu      sync.Mutex
+	batches [][]string
+}
+
+func (s *shipped) ship(batch []string) {
+	if len(batch) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]string(nil), batch...))
+}
+
+func (s *shipped) snapshot() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.batches...)
+}
+
+func newTestBatchLoop(cfg batchConfig) (*batchLoop[string], *shipped) {
+	if cfg.flushInterval == 0 {
+		cfg.flushInterval = time.Hour
+	}
+	s := &shipped{}
+	b := newBatchLoop(cfg, func(item string) int { return len(item) }, s.ship)
+	b.start()
+	return b, s
+}
+
+// waitBatches waits until s holds n batches
+func (s *shipped) waitBatches(t *testing.T, n int) [][]string {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		got := s.snapshot()
+		if len(got) >= n || time.Now().After(deadline) {
+			return got
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchLoopShipsOnSizeAndBytes(t *testing.T) {
+	b, s := newTestBatchLoop(batchConfig{flushItems: 2, flushBytes: 10})
+
+	b.add("a")
+	b.add("b")
+	if got := s.waitBatches(t, 1); len(got) != 1 || len(got[0]) != 2 {
+		t.Fatalf("Expected a batch once flushItems is reached, got %v", got)
+	}
+
+	b.add("0123456789")
+	if got := s.waitBatches(t, 2); len(got) != 2 || got[1][0] != "0123456789" {
+		t.Fatalf("Expected a batch once flushBytes is reached, got %v", got)
+	}
+
+	b.add("left")
+	if err := b.close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := s.snapshot(); len(got) != 3 || got[2][0] != "left" {
+		t.Errorf("Expected close to ship the rest, got %v", got)
+	}
+
+	if b.add("late") {
+		t.Error("Expected items added after close to be dropped")
+	}
+	if dropped := b.dropped.Load(); dropped != 1 {
+		t.Errorf("Expected 1 dropped item, got %d", dropped)
+	}
+}
+
+func TestBatchLoopCloseAbortsWait(t *testing.T) {
+	waited := make(chan bool, 1)
+	b := newBatchLoop(batchConfig{flushInterval: time.Hour}, func(item string) int { return len(item) }, nil)
+	b.ship = func(batch []string) {
+		if len(batch) > 0 {
+			waited <- b.wait(time.Hour)
+		}
+	}
+	b.start()
+
+	b.add("retry")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := b.close(ctx); err == nil {
+		t.Error("Expected close to give up once its context is done")
+	}
+
+	select {
+	case ok := <-waited:
+		if ok {
+			t.Error("Expected the wait to be aborted")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected close to abort the backoff")
+	}
+}
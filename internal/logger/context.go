@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// Fields the HTTP middleware stores in the request context
+const (
+	RequestIDField = "request_id"
+	RouteField     = "route"
+	ClientIPField  = "client_ip"
+	UserIDField    = "user_id"
+)
+
+// contextKey is the private key of the fields carried in a context
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying fields. They are merged over the
+// fields ctx already carries, and every log made with the returned context
+// includes them.
+func WithContext(ctx context.Context, fields map[string]interface{}) context.Context {
+	return context.WithValue(ctx, contextKey{}, mergeFields(FromContext(ctx), fields))
+}
+
+// FromContext returns the fields carried by ctx. The map must not be
+// modified; use WithContext to add fields.
+func FromContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextKey{}).(map[string]interface{})
+	return fields
+}
+
+// timeKey is the private key of the time carried in a context
+type timeKey struct{}
+
+// withTime returns a copy of ctx carrying the time the logs made with it are
+// stamped with, such as the time a slog record was created
+func withTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, timeKey{}, t)
+}
+
+// logTime returns the time carried by ctx, or the current time
+func logTime(ctx context.Context) time.Time {
+	if ctx != nil {
+		if t, ok := ctx.Value(timeKey{}).(time.Time); ok && !t.IsZero() {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// withContextFields returns fields on top of the fields carried by ctx
+func withContextFields(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	carried := FromContext(ctx)
+	if len(carried) == 0 {
+		return fields
+	}
+	return mergeFields(carried, fields)
+}
+
+// mergeFields returns a new map with over on top of base
+func mergeFields(base, over map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(over))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range over {
+		merged[k] = v
+	}
+	return merged
+}
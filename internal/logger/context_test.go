@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithContextMergesFields(t *testing.T) {
+	ctx := WithContext(context.Background(), map[string]interface{}{RequestIDField: "req-1", RouteField: "/users"})
+	child := WithContext(ctx, map[string]interface{}{UserIDField: 42, RouteField: "/users/:id"})
+
+	parent := FromContext(ctx)
+	if len(parent) != 2 || parent[RouteField] != "/users" {
+		t.Errorf("Expected the parent context to be left untouched, got %v", parent)
+	}
+
+	got := FromContext(child)
+	if got[RequestIDField] != "req-1" || got[UserIDField] != 42 || got[RouteField] != "/users/:id" {
+		t.Errorf("Expected the child to carry the merged fields, got %v", got)
+	}
+
+	if FromContext(context.Background()) != nil {
+		t.Error("Expected no fields in a bare context")
+	}
+}
+
+func TestLoggerIncludesContextFields(t *testing.T) {
+	recorder := &recordingLogger{}
+	appLogger := Logger{AppLogger: recorder}
+
+	ctx := WithContext(context.Background(), map[string]interface{}{RequestIDField: "req-1", ClientIPField: "10.0.0.1"})
+	appLogger.LogInfo(ctx, "User created", map[string]interface{}{ClientIPField: "10.0.0.2"})
+
+	got := recorder.logged()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 log, got %d", len(got))
+	}
+	if got[0].fields[RequestIDField] != "req-1" {
+		t.Errorf("Expected the request ID from the context, got %v", got[0].fields)
+	}
+	if got[0].fields[ClientIPField] != "10.0.0.2" {
+		t.Errorf("Expected explicit fields to win over the context, got %v", got[0].fields)
+	}
+}
+
+func TestExtractRequestIDFromContext(t *testing.T) {
+	ctx := WithContext(context.Background(), map[string]interface{}{RequestIDField: "req-1"})
+	if got := extractRequestID(ctx); got != "req-1" {
+		t.Errorf("Expected req-1, got %q", got)
+	}
+	if got := extractRequestID(context.Background()); got != "" {
+		t.Errorf("Expected no request ID, got %q", got)
+	}
+}
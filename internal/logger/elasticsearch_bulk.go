@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+// BulkStats reports the outcome of the documents handed to the bulk indexer
+type BulkStats struct {
+	Indexed uint64 `json:"indexed"`
+	Failed  uint64 `json:"failed"`
+	Dropped uint64 `json:"dropped"`
+	Retried uint64 `json:"retried"`
+}
+
+type bulkConfig struct {
+	flushDocs     int
+	flushBytes    int
+	flushInterval time.Duration
+	queueSize     int
+	maxRetries    int
+}
+
+type bulkDoc struct {
+	index    string
+	body     []byte
+	attempts int
+}
+
+// bulkIndexer ships documents to Elasticsearch through the _bulk API from a
+// batchLoop, so logging never blocks on the cluster. Documents rejected with
+// a retryable status are sent again with the next flush.
+type bulkIndexer struct {
+	client *elasticsearch.Client
+	config bulkConfig
+	loop   *batchLoop[bulkDoc]
+
+	// retries is only touched by the loop goroutine
+	retries []bulkDoc
+
+	indexed atomic.Uint64
+	failed  atomic.Uint64
+	retried atomic.Uint64
+}
+
+func newBulkIndexer(client *elasticsearch.Client, cfg bulkConfig) *bulkIndexer {
+	if cfg.flushDocs <= 0 {
+		cfg.flushDocs = 500
+	}
+	if cfg.flushBytes <= 0 {
+		cfg.flushBytes = 5 << 20
+	}
+	if cfg.queueSize <= 0 {
+		cfg.queueSize = 10000
+	}
+	if cfg.maxRetries < 0 {
+		cfg.maxRetries = 0
+	}
+
+	bi := &bulkIndexer{
+		client: client,
+		config: cfg,
+	}
+	bi.loop = newBatchLoop(batchConfig{
+		flushItems:    cfg.flushDocs,
+		flushBytes:    cfg.flushBytes,
+		flushInterval: cfg.flushInterval,
+		queueSize:     cfg.queueSize,
+	}, func(doc bulkDoc) int { return len(doc.body) }, bi.send)
+	bi.loop.finish = bi.finish
+	bi.loop.start()
+	return bi
+}
+
+// add queues a document without blocking and reports whether it was accepted
+func (bi *bulkIndexer) add(index string, body []byte) bool {
+	return bi.loop.add(bulkDoc{index: index, body: body})
+}
+
+// flush sends everything queued so far and waits for the request to finish
+func (bi *bulkIndexer) flush(ctx context.Context) error {
+	return bi.loop.flush(ctx)
+}
+
+// close stops accepting documents and flushes the queue, retrying failed
+// documents until they run out of attempts or ctx is done
+func (bi *bulkIndexer) close(ctx context.Context) error {
+	if err := bi.loop.close(ctx); err != nil {
+		return fmt.Errorf("elasticsearch bulk indexer did not finish flushing: %w", err)
+	}
+	return nil
+}
+
+func (bi *bulkIndexer) stats() BulkStats {
+	return BulkStats{
+		Indexed: bi.indexed.Load(),
+		Failed:  bi.failed.Load(),
+		Dropped: bi.loop.dropped.Load(),
+		Retried: bi.retried.Load(),
+	}
+}
+
+// send flushes the documents waiting for a retry and the pending batch in
+// requests of at most flushDocs documents
+func (bi *bulkIndexer) send(pending []bulkDoc) {
+	batch := append(bi.retries, pending...)
+	bi.retries = nil
+	chunks(batch, bi.config.flushDocs, bi.sendBatch)
+}
+
+// finish keeps retrying the failed documents with backoff after the last
+// flush, until they run out of attempts or close gives up
+func (bi *bulkIndexer) finish() {
+	for attempt := 1; len(bi.retries) > 0; attempt++ {
+		if !bi.loop.wait(time.Duration(attempt) * 100 * time.Millisecond) {
+			bi.failed.Add(uint64(len(bi.retries)))
+			bi.retries = nil
+			return
+		}
+		bi.send(nil)
+	}
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+func (bi *bulkIndexer) sendBatch(batch []bulkDoc) {
+	var body bytes.Buffer
+	for _, doc := range batch {
+		meta, _ := json.Marshal(map[string]interface{}{
+			"index": map[string]string{"_index": doc.index},
+		})
+		body.Write(meta)
+		body.WriteByte('\n')
+		body.Write(doc.body)
+		body.WriteByte('\n')
+	}
+
+	res, err := bi.client.Bulk(bytes.NewReader(body.Bytes()))
+	if err != nil {
+		fmt.Printf("Failed to send log documents to Elasticsearch: %v\n", err)
+		bi.retryAll(batch)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		fmt.Printf("Elasticsearch bulk request failed: %s\n", res.Status())
+		if retryable(res.StatusCode) {
+			bi.retryAll(batch)
+		} else {
+			bi.failed.Add(uint64(len(batch)))
+		}
+		return
+	}
+
+	var blk bulkResponse
+	if err := json.NewDecoder(res.Body).Decode(&blk); err != nil {
+		fmt.Printf("Failed to parse Elasticsearch bulk response: %v\n", err)
+		bi.failed.Add(uint64(len(batch)))
+		return
+	}
+
+	for i, doc := range batch {
+		if i >= len(blk.Items) {
+			bi.retry(doc)
+			continue
+		}
+		for _, item := range blk.Items[i] {
+			switch {
+			case item.Status < 300:
+				bi.indexed.Add(1)
+			case retryable(item.Status):
+				bi.retry(doc)
+			default:
+				bi.failed.Add(1)
+				fmt.Printf("Elasticsearch rejected log document: [%d] %s: %s\n", item.Status, item.Error.Type, item.Error.Reason)
+			}
+		}
+	}
+}
+
+func (bi *bulkIndexer) retryAll(batch []bulkDoc) {
+	for _, doc := range batch {
+		bi.retry(doc)
+	}
+}
+
+// retry schedules doc for the next flush while it has attempts left. Only as
+// many documents as the queue holds wait for a retry, which bounds memory
+// while the cluster is unavailable.
+func (bi *bulkIndexer) retry(doc bulkDoc) {
+	doc.attempts++
+	if doc.attempts > bi.config.maxRetries || len(bi.retries) >= bi.config.queueSize {
+		bi.failed.Add(1)
+		return
+	}
+	bi.retried.Add(1)
+	bi.retries = append(bi.retries, doc)
+}
+
+// retryable reports whether a bulk status is worth retrying
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
@@ -1,7 +1,6 @@
 package logger
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,17 +10,26 @@ import (
 	"github.com/your-org/boilerplate-go/internal/config"
 )
 
+const elasticsearchDefaultURL = "http://localhost:9200"
+
+// ElasticsearchLogger ships ECS documents to Elasticsearch. Log only queues
+// the document; a bulk indexer sends it in the background.
 type ElasticsearchLogger struct {
 	config    config.LoggerConfig
 	client    *elasticsearch.Client
-	fields    map[string]interface{}
+	indexer   *bulkIndexer
 	indexName string
 }
 
 func NewElasticsearchLogger(cfg config.LoggerConfig) *ElasticsearchLogger {
+	url := cfg.Url
+	if url == "" {
+		url = elasticsearchDefaultURL
+	}
+
 	// Elasticsearch client configuration
 	esCfg := elasticsearch.Config{
-		Addresses: []string{cfg.Url},
+		Addresses: []string{url},
 	}
 
 	// Add authentication if provided
@@ -46,18 +54,42 @@ func NewElasticsearchLogger(cfg config.LoggerConfig) *ElasticsearchLogger {
 	}
 
 	return &ElasticsearchLogger{
-		config:    cfg,
-		client:    client,
-		fields:    make(map[string]interface{}),
+		config: cfg,
+		client: client,
+		indexer: newBulkIndexer(client, bulkConfig{
+			flushDocs:     cfg.BulkSize,
+			flushBytes:    cfg.BulkFlushBytes,
+			flushInterval: cfg.FlushInterval,
+			queueSize:     cfg.QueueSize,
+			maxRetries:    cfg.MaxRetries,
+		}),
 		indexName: indexName,
 	}
 }
 
-func (l *ElasticsearchLogger) AddField(key string, value interface{}) {
-	if l.fields == nil {
-		l.fields = make(map[string]interface{})
+// Flush sends the queued documents and waits for Elasticsearch to answer
+func (l *ElasticsearchLogger) Flush(ctx context.Context) error {
+	return l.indexer.flush(ctx)
+}
+
+// Close stops accepting documents and flushes the ones still queued
+func (l *ElasticsearchLogger) Close(ctx context.Context) error {
+	err := l.indexer.close(ctx)
+
+	if stats := l.indexer.stats(); stats.Failed > 0 || stats.Dropped > 0 {
+		fmt.Printf("Elasticsearch logger closed: %d indexed, %d failed, %d dropped\n", stats.Indexed, stats.Failed, stats.Dropped)
 	}
-	l.fields[key] = value
+	return err
+}
+
+// Stats reports how many documents were indexed, failed or dropped
+func (l *ElasticsearchLogger) Stats() BulkStats {
+	return l.indexer.stats()
+}
+
+// With returns a child logger that adds fields to every log
+func (l *ElasticsearchLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
 }
 
 func (l *ElasticsearchLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
@@ -66,18 +98,11 @@ func (l *ElasticsearchLogger) Log(ctx context.Context, level, message string, fi
 		return
 	}
 
-	// Merge stored fields with provided fields
-	mergedFields := make(map[string]interface{})
-	for k, v := range l.fields {
-		mergedFields[k] = v
-	}
-	for k, v := range fields {
-		mergedFields[k] = v
-	}
+	now := logTime(ctx).UTC()
 
 	// Create ECS-compliant log document
 	doc := map[string]interface{}{
-		"@timestamp": time.Now().UTC().Format(time.RFC3339),
+		"@timestamp": now.Format(time.RFC3339),
 		"log": map[string]interface{}{
 			"level": level,
 		},
@@ -91,7 +116,7 @@ func (l *ElasticsearchLogger) Log(ctx context.Context, level, message string, fi
 	}
 
 	// Add custom fields
-	for key, value := range mergedFields {
+	for key, value := range fields {
 		doc[key] = value
 	}
 
@@ -110,44 +135,13 @@ func (l *ElasticsearchLogger) Log(ctx context.Context, level, message string, fi
 	}
 
 	// Create index name with date for daily rotation
-	indexName := fmt.Sprintf("%s-%s", l.indexName, time.Now().UTC().Format("2006.01.02"))
-
-	// Send document to Elasticsearch
-	res, err := l.client.Index(
-		indexName,
-		bytes.NewReader(docBytes),
-		l.client.Index.WithContext(ctx),
-		l.client.Index.WithRefresh("true"),
-	)
-	if err != nil {
-		fmt.Printf("Failed to index log document: %v\n", err)
-		return
-	}
-	defer res.Body.Close()
+	indexName := fmt.Sprintf("%s-%s", l.indexName, now.Format("2006.01.02"))
 
-	if res.IsError() {
-		fmt.Printf("Elasticsearch indexing failed: %s\n", res.String())
-	}
+	l.indexer.add(indexName, docBytes)
 }
 
-// extractRequestID extracts request ID from context
+// extractRequestID extracts request ID from the fields carried by ctx
 func extractRequestID(ctx context.Context) string {
-	if ctx == nil {
-		return ""
-	}
-
-	// Try to extract from common context keys
-	if requestID := ctx.Value("x-request-id"); requestID != nil {
-		if id, ok := requestID.(string); ok {
-			return id
-		}
-	}
-
-	if requestID := ctx.Value("request-id"); requestID != nil {
-		if id, ok := requestID.(string); ok {
-			return id
-		}
-	}
-
-	return ""
+	id, _ := FromContext(ctx)[RequestIDField].(string)
+	return id
 }
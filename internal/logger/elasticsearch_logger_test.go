@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// fakeBulk is an httptest stand-in for the Elasticsearch _bulk API. status
+// picks the status of every document; it sees how often the message was
+// already sent.
+type fakeBulk struct {
+	mu       sync.Mutex
+	requests int
+	attempts map[string]int
+	indexed  []string
+	status   func(message string, attempt int) int
+}
+
+func newFakeBulk(t *testing.T, status func(message string, attempt int) int) (*fakeBulk, *httptest.Server) {
+	fake := &fakeBulk{attempts: make(map[string]int), status: status}
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeBulk) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	w.Header().Set("Content-Type", "application/json")
+	if !strings.HasSuffix(r.URL.Path, "/_bulk") {
+		w.Write([]byte(`{}`))
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	var items []string
+	hasErrors := false
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		// Skip the action line and read the document
+		if !scanner.Scan() {
+			break
+		}
+		var doc struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(scanner.Bytes(), &doc)
+
+		attempt := f.attempts[doc.Message]
+		f.attempts[doc.Message]++
+
+		status := http.StatusCreated
+		if f.status != nil {
+			status = f.status(doc.Message, attempt)
+		}
+		if status < 300 {
+			f.indexed = append(f.indexed, doc.Message)
+			items = append(items, fmt.Sprintf(`{"index":{"status":%d}}`, status))
+		} else {
+			hasErrors = true
+			items = append(items, fmt.Sprintf(`{"index":{"status":%d,"error":{"type":"error","reason":"rejected"}}}`, status))
+		}
+	}
+
+	fmt.Fprintf(w, `{"took":1,"errors":%t,"items":[%s]}`, hasErrors, strings.Join(items, ","))
+}
+
+func (f *fakeBulk) snapshot() (int, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests, append([]string(nil), f.indexed...)
+}
+
+func newTestElasticsearchLogger(url string, cfg config.LoggerConfig) *ElasticsearchLogger {
+	cfg.Url = url
+	cfg.Index = "test-logs"
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Hour
+	}
+	return NewElasticsearchLogger(cfg)
+}
+
+func TestElasticsearchLoggerBatchesBySize(t *testing.T) {
+	fake, server := newFakeBulk(t, nil)
+	l := newTestElasticsearchLogger(server.URL, config.LoggerConfig{BulkSize: 3})
+	defer l.Close(context.Background())
+
+	for i := 0; i < 6; i++ {
+		l.Log(context.Background(), "info", fmt.Sprintf("message %d", i), nil)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if stats := l.Stats(); stats.Indexed == 6 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	requests, indexed := fake.snapshot()
+	if requests != 2 || len(indexed) != 6 {
+		t.Errorf("Expected 6 documents in 2 bulk requests, got %d in %d", len(indexed), requests)
+	}
+}
+
+func TestElasticsearchLoggerFlushesOnInterval(t *testing.T) {
+	_, server := newFakeBulk(t, nil)
+	l := newTestElasticsearchLogger(server.URL, config.LoggerConfig{FlushInterval: 20 * time.Millisecond})
+	defer l.Close(context.Background())
+
+	l.Log(context.Background(), "info", "tick", nil)
+
+	deadline := time.Now().Add(time.Second)
+	for l.Stats().Indexed != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the document to be flushed by the interval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestElasticsearchLoggerRetriesRejectedDocuments(t *testing.T) {
+	fake, server := newFakeBulk(t, func(message string, attempt int) int {
+		switch {
+		case message == "throttled" && attempt == 0:
+			return http.StatusTooManyRequests
+		case message == "invalid":
+			return http.StatusBadRequest
+		case message == "unavailable":
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusCreated
+	})
+	l := newTestElasticsearchLogger(server.URL, config.LoggerConfig{MaxRetries: 2})
+
+	for _, message := range []string{"ok", "throttled", "invalid", "unavailable"} {
+		l.Log(context.Background(), "info", message, nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.Close(ctx); err != nil {
+		t.Fatalf("Expected close to flush, got %v", err)
+	}
+
+	stats := l.Stats()
+	if stats.Indexed != 2 || stats.Failed != 2 || stats.Retried != 3 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if _, indexed := fake.snapshot(); len(indexed) != 2 {
+		t.Errorf("Expected ok and throttled to be indexed, got %v", indexed)
+	}
+	if got := fake.attempts["unavailable"]; got != 3 {
+		t.Errorf("Expected unavailable to be sent 3 times, got %d", got)
+	}
+}
+
+func TestElasticsearchLoggerDropsWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Write([]byte(`{"errors":false,"items":[{"index":{"status":201}}]}`))
+	}))
+	defer server.Close()
+
+	l := newTestElasticsearchLogger(server.URL, config.LoggerConfig{BulkSize: 1, QueueSize: 2})
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		l.Log(context.Background(), "info", "message", nil)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Log should not wait for Elasticsearch, took %s", elapsed)
+	}
+
+	close(release)
+	l.Close(context.Background())
+
+	if stats := l.Stats(); stats.Dropped == 0 || stats.Indexed+stats.Dropped != 20 {
+		t.Errorf("Expected overflowing documents to be dropped, got %+v", stats)
+	}
+}
+
+func TestLoggerCloseFlushesProvider(t *testing.T) {
+	fake, server := newFakeBulk(t, nil)
+	appLogger := Logger{AppLogger: newTestElasticsearchLogger(server.URL, config.LoggerConfig{})}
+
+	appLogger.LogInfo(context.Background(), "shutting down")
+	if err := appLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, indexed := fake.snapshot(); len(indexed) != 1 || indexed[0] != "shutting down" {
+		t.Errorf("Expected the final flush to index the document, got %v", indexed)
+	}
+}
+
+func TestElasticsearchLoggerCloseCutsRetryBackoff(t *testing.T) {
+	_, server := newFakeBulk(t, func(message string, attempt int) int { return http.StatusServiceUnavailable })
+	l := newTestElasticsearchLogger(server.URL, config.LoggerConfig{MaxRetries: 100})
+
+	l.Log(context.Background(), "info", "never accepted", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected close to honor its context, took %s", elapsed)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Stats().Failed != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the abandoned document to be counted as failed, got %+v", l.Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -4,78 +4,107 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/rs/zerolog"
 	"github.com/your-org/boilerplate-go/internal/config"
 )
 
+// FileLogger writes JSON lines to a rotated log file. The file stays open
+// behind a buffered writer and is reopened on SIGHUP, so an external
+// logrotate can move it away.
 type FileLogger struct {
 	config config.LoggerConfig
-	fields map[string]interface{}
+	writer *fileRotator
+	logger zerolog.Logger
+	hup    chan os.Signal
+	once   sync.Once
 }
 
 func NewFileLogger(cfg config.LoggerConfig) *FileLogger {
-	return &FileLogger{
-		config: cfg,
-		fields: make(map[string]interface{}),
+	writer, err := newFileRotator(cfg.Filepath, rotatorConfig{
+		maxSize:        int64(cfg.MaxSizeMB) << 20,
+		rotateInterval: cfg.RotateInterval,
+		maxBackups:     cfg.MaxBackups,
+		maxAge:         cfg.MaxAge,
+		compress:       cfg.Compress,
+		flushInterval:  cfg.FlushInterval,
+	})
+	if err != nil {
+		fmt.Printf("Failed to open log file: %v\n", err)
+		return nil
 	}
-}
-
-func (l *FileLogger) AddField(key string, value interface{}) {
-	l.fields[key] = value
-}
 
-func (l *FileLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
-	// Ensure log directory exists
-	logDir := filepath.Dir(l.config.Filepath)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		fmt.Printf("Failed to create log directory: %v\n", err)
-		return
+	l := &FileLogger{
+		config: cfg,
+		writer: writer,
+		logger: zerolog.New(writer),
+		hup:    make(chan os.Signal, 1),
 	}
 
-	// Open log file for writing
-	file, err := os.OpenFile(l.config.Filepath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-	if err != nil {
-		fmt.Printf("Failed to open log file: %v\n", err)
-		return
-	}
-	defer file.Close()
+	signal.Notify(l.hup, syscall.SIGHUP)
+	go l.reopenOnSignal()
 
-	// Create logger with JSON format for file
-	logger := zerolog.New(file).With().Timestamp().Logger()
+	return l
+}
 
-	// Merge stored fields with provided fields
-	mergedFields := make(map[string]interface{})
-	for k, v := range l.fields {
-		mergedFields[k] = v
-	}
-	for k, v := range fields {
-		mergedFields[k] = v
-	}
+// With returns a child logger that adds fields to every log
+func (l *FileLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
 
+func (l *FileLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
 	// Create log event based on level
 	var event *zerolog.Event
 	switch level {
 	case "debug":
-		event = logger.Debug()
+		event = l.logger.Debug()
 	case "info":
-		event = logger.Info()
+		event = l.logger.Info()
 	case "warn":
-		event = logger.Warn()
+		event = l.logger.Warn()
 	case "error":
-		event = logger.Error()
+		event = l.logger.Error()
 	case "fatal":
-		event = logger.Fatal()
+		// Flush the buffer before exiting
+		event = l.logger.WithLevel(zerolog.FatalLevel)
+		defer os.Exit(1)
+		defer l.writer.Flush()
 	default:
-		event = logger.Info()
+		event = l.logger.Info()
 	}
 
+	event = event.Time(zerolog.TimestampFieldName, logTime(ctx))
+
 	// Add fields to event
-	for key, value := range mergedFields {
+	for key, value := range fields {
 		event = event.Interface(key, value)
 	}
 
 	// Log the message
 	event.Msg(message)
 }
+
+// Reopen reopens the log file after it was moved or removed
+func (l *FileLogger) Reopen() error {
+	return l.writer.Reopen()
+}
+
+// Close stops listening for SIGHUP and flushes and closes the log file
+func (l *FileLogger) Close(ctx context.Context) error {
+	l.once.Do(func() {
+		signal.Stop(l.hup)
+		close(l.hup)
+	})
+	return l.writer.Close()
+}
+
+func (l *FileLogger) reopenOnSignal() {
+	for range l.hup {
+		if err := l.writer.Reopen(); err != nil {
+			fmt.Printf("Failed to reopen log file: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// fakeClock is a settable clock for the rotator
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestRotator(t *testing.T, cfg rotatorConfig) (*fileRotator, *fakeClock) {
+	t.Helper()
+
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	r, err := newFileRotator(filepath.Join(t.TempDir(), "app.log"), cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	r.now = clock.now
+	if cfg.rotateInterval > 0 {
+		r.nextRotation = clock.now().Truncate(cfg.rotateInterval).Add(cfg.rotateInterval)
+	}
+	return r, clock
+}
+
+func listBackups(t *testing.T, r *fileRotator) []string {
+	t.Helper()
+
+	backups, err := r.backups()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	names := make([]string, len(backups))
+	for i, backup := range backups {
+		names[i] = filepath.Base(backup.path)
+	}
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return string(data)
+}
+
+func TestFileLoggerKeepsFileOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	l := NewFileLogger(config.LoggerConfig{Filepath: path})
+
+	l.Log(context.Background(), "info", "first", map[string]interface{}{"user_id": 1})
+	l.Log(context.Background(), "warn", "second", nil)
+
+	// Logs stay in the buffer until a flush
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.Fatalf("Expected an empty open file before flushing, got %v, %v", info, err)
+	}
+
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	content := readFile(t, path)
+	if !strings.Contains(content, `"message":"first"`) || !strings.Contains(content, `"user_id":1`) || !strings.Contains(content, `"message":"second"`) {
+		t.Errorf("Expected both logs in the file, got %s", content)
+	}
+}
+
+func TestFileRotatorRotatesBySize(t *testing.T) {
+	r, clock := newTestRotator(t, rotatorConfig{maxSize: 10})
+
+	r.Write([]byte("0123456789"))
+	clock.advance(time.Second)
+	r.Write([]byte("abc"))
+	clock.advance(time.Second)
+	r.Write([]byte("defghijklmn"))
+	r.Close()
+
+	backups := listBackups(t, r)
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 rotated files, got %v", backups)
+	}
+	if got := readFile(t, r.path); got != "defghijklmn" {
+		t.Errorf("Expected the latest write in the current file, got %q", got)
+	}
+	if got := readFile(t, filepath.Join(filepath.Dir(r.path), backups[1])); got != "0123456789" {
+		t.Errorf("Expected the first write in the oldest backup, got %q", got)
+	}
+}
+
+func TestFileRotatorRotatesAfterCloseError(t *testing.T) {
+	r, _ := newTestRotator(t, rotatorConfig{maxSize: 10})
+	defer r.Close()
+
+	r.Write([]byte("0123456789"))
+	// Closing under the buffer makes the flush on rotation fail
+	r.file.Close()
+
+	if _, err := r.Write([]byte("abc")); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Expected writes to reach the new file, got %v", err)
+	}
+	if got := readFile(t, r.path); got != "abc" {
+		t.Errorf("Expected the new file to be written, got %q", got)
+	}
+}
+
+func TestFileRotatorReopensAfterCloseError(t *testing.T) {
+	r, _ := newTestRotator(t, rotatorConfig{})
+	defer r.Close()
+
+	r.Write([]byte("lost"))
+	r.file.Close()
+
+	if err := r.Reopen(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	r.Write([]byte("kept"))
+	if err := r.Flush(); err != nil {
+		t.Fatalf("Expected writes to reach the reopened file, got %v", err)
+	}
+	if got := readFile(t, r.path); got != "kept" {
+		t.Errorf("Expected the reopened file to be written, got %q", got)
+	}
+}
+
+func TestFileRotatorRotatesByTime(t *testing.T) {
+	r, clock := newTestRotator(t, rotatorConfig{rotateInterval: time.Hour})
+
+	r.Write([]byte("before\n"))
+	clock.advance(30 * time.Minute)
+	r.Write([]byte("same hour\n"))
+	clock.advance(30 * time.Minute)
+	r.Write([]byte("next hour\n"))
+	r.Close()
+
+	backups := listBackups(t, r)
+	if len(backups) != 1 || backups[0] != "app-2024-01-01T13-00-00.000.log" {
+		t.Fatalf("Expected one rotation at the hour, got %v", backups)
+	}
+	if got := readFile(t, r.path); got != "next hour\n" {
+		t.Errorf("Unexpected current file: %q", got)
+	}
+}
+
+func TestFileRotatorCompressesBackups(t *testing.T) {
+	r, _ := newTestRotator(t, rotatorConfig{maxSize: 5, compress: true})
+
+	r.Write([]byte("hello"))
+	r.Write([]byte("world"))
+	r.Close()
+
+	backups := listBackups(t, r)
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".log.gz") {
+		t.Fatalf("Expected one gzipped backup, got %v", backups)
+	}
+
+	file, err := os.Open(filepath.Join(filepath.Dir(r.path), backups[0]))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Expected a gzip file, got %v", err)
+	}
+	if data, _ := io.ReadAll(gz); string(data) != "hello" {
+		t.Errorf("Unexpected backup content: %q", data)
+	}
+}
+
+func TestFileRotatorRetention(t *testing.T) {
+	r, clock := newTestRotator(t, rotatorConfig{maxSize: 1, maxBackups: 3, maxAge: 48 * time.Hour})
+
+	// a is rotated a week before the others and expires by age
+	r.Write([]byte("a"))
+	r.Write([]byte("b"))
+	clock.advance(7 * 24 * time.Hour)
+	r.Write([]byte("c"))
+	clock.advance(time.Minute)
+	r.Write([]byte("d"))
+	r.Close()
+
+	if backups := listBackups(t, r); len(backups) != 2 {
+		t.Fatalf("Expected the expired backup to be removed, got %v", backups)
+	}
+
+	// Only the newest maxBackups are kept
+	r, clock = newTestRotator(t, rotatorConfig{maxSize: 1, maxBackups: 3})
+	for _, p := range []string{"a", "b", "c", "d", "e", "f"} {
+		r.Write([]byte(p))
+		clock.advance(time.Minute)
+	}
+	r.Close()
+
+	backups := listBackups(t, r)
+	if len(backups) != 3 {
+		t.Fatalf("Expected 3 backups to be kept, got %v", backups)
+	}
+	if got := readFile(t, filepath.Join(filepath.Dir(r.path), backups[2])); got != "c" {
+		t.Errorf("Expected the oldest kept backup to be c, got %q", got)
+	}
+}
+
+func TestFileLoggerReopensOnSIGHUP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l := NewFileLogger(config.LoggerConfig{Filepath: path})
+	defer l.Close(context.Background())
+
+	l.Log(context.Background(), "info", "before", nil)
+	l.writer.Flush()
+
+	// Simulate logrotate moving the file away
+	moved := path + ".1"
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the log file to be reopened")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	l.Log(context.Background(), "info", "after", nil)
+	l.writer.Flush()
+
+	if content := readFile(t, path); !strings.Contains(content, "after") || strings.Contains(content, "before") {
+		t.Errorf("Expected only new logs in the reopened file, got %s", content)
+	}
+	if content := readFile(t, moved); !strings.Contains(content, "before") {
+		t.Errorf("Expected old logs in the moved file, got %s", content)
+	}
+}
@@ -0,0 +1,344 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+type rotatorConfig struct {
+	maxSize        int64
+	rotateInterval time.Duration
+	maxBackups     int
+	maxAge         time.Duration
+	compress       bool
+	flushInterval  time.Duration
+}
+
+// fileRotator is a buffered io.Writer over a log file that stays open between
+// writes. The file is rotated once it reaches maxSize or rotateInterval
+// elapses; rotated files are renamed to <name>-<time><ext>, optionally
+// gzipped, and pruned by count and age in the background.
+type fileRotator struct {
+	path   string
+	config rotatorConfig
+	now    func() time.Time
+
+	mu           sync.Mutex
+	file         *os.File
+	buf          *bufio.Writer
+	size         int64
+	nextRotation time.Time
+	closed       bool
+
+	millMu sync.Mutex
+	wg     sync.WaitGroup
+	stop   chan struct{}
+}
+
+func newFileRotator(path string, cfg rotatorConfig) (*fileRotator, error) {
+	r := &fileRotator{
+		path:   path,
+		config: cfg,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+	}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	if cfg.flushInterval > 0 {
+		r.wg.Add(1)
+		go r.flushLoop()
+	}
+	return r, nil
+}
+
+func (r *fileRotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			fmt.Printf("Failed to rotate log file: %v\n", err)
+		}
+	}
+
+	n, err := r.buf.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Flush writes the buffered logs to the file
+func (r *fileRotator) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	return r.buf.Flush()
+}
+
+// Reopen closes and reopens the log file, so that logs go to a new file after
+// an external tool such as logrotate moved the old one away
+func (r *fileRotator) Reopen() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return os.ErrClosed
+	}
+	// The file is closed even when closing fails, so open the new one anyway
+	if err := r.closeFile(); err != nil {
+		fmt.Printf("Failed to close the previous log file: %v\n", err)
+	}
+	return r.open()
+}
+
+// Close flushes the buffer, closes the file and waits for background
+// compression and cleanup to finish
+func (r *fileRotator) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.stop)
+	err := r.closeFile()
+	r.mu.Unlock()
+
+	r.wg.Wait()
+	return err
+}
+
+func (r *fileRotator) flushLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Flush(); err != nil {
+				fmt.Printf("Failed to flush log file: %v\n", err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// open opens the log file for appending. Callers must hold r.mu.
+func (r *fileRotator) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	r.file = file
+	r.buf = bufio.NewWriterSize(file, 64*1024)
+	r.size = info.Size()
+	if r.config.rotateInterval > 0 {
+		r.nextRotation = r.now().Truncate(r.config.rotateInterval).Add(r.config.rotateInterval)
+	}
+	return nil
+}
+
+// closeFile flushes and closes the current file. Callers must hold r.mu.
+func (r *fileRotator) closeFile() error {
+	flushErr := r.buf.Flush()
+	closeErr := r.file.Close()
+	if flushErr != nil {
+		return fmt.Errorf("failed to flush log file: %w", flushErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close log file: %w", closeErr)
+	}
+	return nil
+}
+
+// shouldRotate reports whether writing n more bytes must go to a new file.
+// Callers must hold r.mu.
+func (r *fileRotator) shouldRotate(n int64) bool {
+	if r.config.maxSize > 0 && r.size > 0 && r.size+n > r.config.maxSize {
+		return true
+	}
+	return r.config.rotateInterval > 0 && !r.now().Before(r.nextRotation)
+}
+
+// rotate moves the current file aside, opens a fresh one and hands the
+// rotated file to the background mill. Callers must hold r.mu.
+func (r *fileRotator) rotate() error {
+	// The file is closed even when closing fails, so rotate anyway
+	if err := r.closeFile(); err != nil {
+		fmt.Printf("Failed to close the previous log file: %v\n", err)
+	}
+
+	backup := r.backupName(r.now())
+	if err := os.Rename(r.path, backup); err != nil {
+		// Keep logging to the current file
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rename log file: %w", err)
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.mill(backup)
+	}()
+	return nil
+}
+
+func (r *fileRotator) backupName(t time.Time) string {
+	dir := filepath.Dir(r.path)
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext)
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, t.UTC().Format(backupTimeFormat), ext))
+}
+
+// mill compresses a freshly rotated file and removes the backups that exceed
+// maxBackups or maxAge
+func (r *fileRotator) mill(backup string) {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	if r.config.compress {
+		if err := compressFile(backup); err != nil {
+			fmt.Printf("Failed to compress rotated log file: %v\n", err)
+		}
+	}
+
+	if err := r.removeOldBackups(); err != nil {
+		fmt.Printf("Failed to remove old log files: %v\n", err)
+	}
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+// backups lists the rotated files of r.path, newest first
+func (r *fileRotator) backups() ([]backupFile, error) {
+	dir := filepath.Dir(r.path)
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(filepath.Base(r.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, name), time: t})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+func (r *fileRotator) removeOldBackups() error {
+	if r.config.maxBackups <= 0 && r.config.maxAge <= 0 {
+		return nil
+	}
+
+	backups, err := r.backups()
+	if err != nil {
+		return err
+	}
+
+	cutoff := r.now().Add(-r.config.maxAge)
+	for i, backup := range backups {
+		tooMany := r.config.maxBackups > 0 && i >= r.config.maxBackups
+		tooOld := r.config.maxAge > 0 && backup.time.Before(cutoff)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// compressFile gzips path into path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelController holds the minimum level of every provider. The level can
+// be changed at runtime, either for good or as an override that reverts to
+// the configured level once its TTL expires.
+type LevelController struct {
+	mu        sync.RWMutex
+	base      zerolog.Level
+	override  *zerolog.Level
+	expiresAt time.Time
+	now       func() time.Time
+}
+
+// LevelState describes the effective level and the override, if any
+type LevelState struct {
+	Level     string     `json:"level"`
+	Base      string     `json:"base"`
+	Override  bool       `json:"override"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func NewLevelController(level string) *LevelController {
+	return &LevelController{
+		base: parseLogLevel(level),
+		now:  time.Now,
+	}
+}
+
+// ParseLevel parses a level name, rejecting unknown ones
+func ParseLevel(level string) (zerolog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug", "info", "warn", "warning", "error", "fatal", "panic":
+		return parseLogLevel(level), nil
+	}
+	return zerolog.NoLevel, fmt.Errorf("unknown log level %q", level)
+}
+
+// Level returns the effective minimum level
+func (c *LevelController) Level() zerolog.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.levelLocked()
+}
+
+// Enabled reports whether logs of level should be written
+func (c *LevelController) Enabled(level string) bool {
+	return parseLogLevel(level) >= c.Level()
+}
+
+// Override returns the runtime override while it is active
+func (c *LevelController) Override() (zerolog.Level, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.activeLocked() {
+		return *c.override, true
+	}
+	return zerolog.NoLevel, false
+}
+
+// Set changes the level. With a positive ttl the change is a temporary
+// override that reverts to the previous base level once it expires; without
+// one it becomes the new base level.
+func (c *LevelController) Set(level string, ttl time.Duration) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl > 0 {
+		c.override = &parsed
+		c.expiresAt = c.now().Add(ttl)
+		return nil
+	}
+
+	c.base = parsed
+	c.override = nil
+	return nil
+}
+
+// Reset drops the override and returns to the base level
+func (c *LevelController) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.override = nil
+}
+
+// State returns the effective level and when the override expires
+func (c *LevelController) State() LevelState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	state := LevelState{
+		Level: c.levelLocked().String(),
+		Base:  c.base.String(),
+	}
+	if c.activeLocked() {
+		expiresAt := c.expiresAt
+		state.Override = true
+		state.ExpiresAt = &expiresAt
+	}
+	return state
+}
+
+// levelLocked returns the override while it has not expired. Expired
+// overrides are ignored rather than cleared, so readers only need the read
+// lock. Callers must hold c.mu.
+func (c *LevelController) levelLocked() zerolog.Level {
+	if c.activeLocked() {
+		return *c.override
+	}
+	return c.base
+}
+
+func (c *LevelController) activeLocked() bool {
+	return c.override != nil && c.now().Before(c.expiresAt)
+}
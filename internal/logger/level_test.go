@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLevelControllerOverrideExpires(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	levels := NewLevelController("info")
+	levels.now = clock.now
+
+	if levels.Enabled("debug") {
+		t.Fatal("Expected debug to be disabled at info")
+	}
+
+	if err := levels.Set("debug", 15*time.Minute); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	state := levels.State()
+	if !levels.Enabled("debug") || state.Level != "debug" || state.Base != "info" || !state.Override {
+		t.Fatalf("Expected a debug override, got %+v", state)
+	}
+	if want := clock.now().Add(15 * time.Minute); state.ExpiresAt == nil || !state.ExpiresAt.Equal(want) {
+		t.Errorf("Expected the override to expire at %s, got %v", want, state.ExpiresAt)
+	}
+
+	clock.advance(15 * time.Minute)
+	if levels.Enabled("debug") || levels.Level() != zerolog.InfoLevel {
+		t.Error("Expected the override to revert after its TTL")
+	}
+	if state := levels.State(); state.Override || state.ExpiresAt != nil {
+		t.Errorf("Expected no override after expiry, got %+v", state)
+	}
+}
+
+func TestLevelControllerSetWithoutTTLChangesBase(t *testing.T) {
+	levels := NewLevelController("info")
+	levels.Set("debug", time.Minute)
+
+	if err := levels.Set("error", 0); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state := levels.State(); state.Level != "error" || state.Base != "error" || state.Override {
+		t.Errorf("Expected a new base level without override, got %+v", state)
+	}
+
+	if err := levels.Set("verbose", 0); err == nil {
+		t.Error("Expected unknown levels to be rejected")
+	}
+}
+
+func TestLevelControllerReset(t *testing.T) {
+	levels := NewLevelController("warn")
+	levels.Set("debug", time.Hour)
+	levels.Reset()
+
+	if levels.Level() != zerolog.WarnLevel {
+		t.Errorf("Expected reset to return to the base level, got %s", levels.Level())
+	}
+}
+
+func TestLoggerRespectsLevelController(t *testing.T) {
+	recorder := &recordingLogger{}
+	appLogger := Logger{AppLogger: recorder, Levels: NewLevelController("warn")}
+
+	appLogger.LogInfo(context.Background(), "hidden")
+	appLogger.Levels.Set("debug", time.Minute)
+	appLogger.LogDebug(context.Background(), "shown")
+
+	got := recorder.logged()
+	if len(got) != 1 || got[0].message != "shown" {
+		t.Errorf("Expected only the log after the override, got %+v", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/your-org/boilerplate-go/internal/config"
+	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -13,10 +14,47 @@ import (
 type Logger struct {
 	Logger    zerolog.Logger
 	AppLogger AppLogger
+	Levels    *LevelController
+	Redactor  *Redactor
+	config    config.LoggerConfig
+	sampler   *Sampler
+	fields    map[string]interface{}
+}
+
+// LoggerInfo describes the effective logger configuration
+type LoggerInfo struct {
+	Level     LevelState     `json:"level"`
+	Providers []ProviderInfo `json:"providers"`
+}
+
+// ProviderInfo describes the effective configuration of a provider. Sinks
+// without a level follow the Logger level.
+type ProviderInfo struct {
+	Provider   string                `json:"provider"`
+	Level      string                `json:"level,omitempty"`
+	Format     string                `json:"format,omitempty"`
+	Sampling   config.SamplingConfig `json:"sampling"`
+	Suppressed uint64                `json:"suppressed"`
+	Dropped    uint64                `json:"dropped"`
+}
+
+// Option customizes the providers created by InitLogger
+type Option func(*options)
+
+type options struct {
+	resource *resource.Resource
+}
+
+// WithResource sets the OpenTelemetry resource the otlp provider describes
+// the service with, so that logs match traces and metrics
+func WithResource(res *resource.Resource) Option {
+	return func(o *options) {
+		o.resource = res
+	}
 }
 
 // InitLogger initializes the logger based on configuration
-func InitLogger(cfg config.LoggerConfig) Logger {
+func InitLogger(cfg config.LoggerConfig, opts ...Option) Logger {
 	// Set zerolog error stack marshaler for better error handling
 	zerolog.ErrorStackMarshaler = func(err error) interface{} {
 		return err.Error()
@@ -25,29 +63,97 @@ func InitLogger(cfg config.LoggerConfig) Logger {
 	var logger zerolog.Logger
 	var appLogger AppLogger
 
+	var sampler *Sampler
+
+	levels := NewLevelController(cfg.Level)
+
+	if len(cfg.Sinks) > 0 {
+		multi := NewMultiLogger(cfg, opts...)
+		multi.levels = levels
+		appLogger = multi
+	} else {
+		appLogger = newProvider(cfg, newOptions(opts))
+		sampler = NewSampler(cfg.Sampling)
+		if appLogger != nil && sampler != nil {
+			appLogger = &sampledLogger{AppLogger: appLogger, sampler: sampler}
+		}
+	}
+
+	redactor, err := NewRedactor(cfg.Redaction)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid log redaction configuration")
+	}
+
+	return Logger{
+		Logger:    logger,
+		AppLogger: appLogger,
+		Levels:    levels,
+		Redactor:  redactor,
+		config:    cfg,
+		sampler:   sampler,
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// newProvider creates the AppLogger of cfg.Provider. It returns nil when the
+// provider fails to start.
+func newProvider(cfg config.LoggerConfig, o options) AppLogger {
 	switch cfg.Provider {
 	case "stdout":
-		appLogger = NewStdoutLogger(cfg)
+		return NewStdoutLogger(cfg)
 	case "file":
-		appLogger = NewFileLogger(cfg)
+		if l := NewFileLogger(cfg); l != nil {
+			return l
+		}
 	case "logstash":
-		appLogger = NewLogstashLogger(cfg)
+		if l := NewLogstashLogger(cfg); l != nil {
+			return l
+		}
 	case "elasticsearch":
-		appLogger = NewElasticsearchLogger(cfg)
+		if l := NewElasticsearchLogger(cfg); l != nil {
+			return l
+		}
+	case "otlp":
+		if l := NewOTLPLogger(cfg, o.resource); l != nil {
+			return l
+		}
+	case "loki":
+		if l := NewLokiLogger(cfg); l != nil {
+			return l
+		}
+	case "syslog":
+		if l := NewSyslogLogger(cfg); l != nil {
+			return l
+		}
 	default:
-		log.Fatal().Msg("Invalid logger provider specified")
-	}
-
-	return Logger{
-		Logger:    logger,
-		AppLogger: appLogger,
+		log.Fatal().Msgf("Invalid logger provider specified: %s", cfg.Provider)
 	}
+	return nil
 }
 
 // Log logs a message with the specified level and fields
 func (l *Logger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
-	// Enrich fields with OpenTelemetry trace information
-	enrichedFields := l.enrichWithTraceInfo(ctx, fields)
+	if !l.enabled(level) {
+		return
+	}
+
+	if len(l.fields) > 0 {
+		fields = mergeFields(l.fields, fields)
+	}
+
+	// Enrich fields with the request scoped fields and OpenTelemetry trace information
+	enrichedFields := l.enrichWithTraceInfo(ctx, withContextFields(ctx, fields))
+
+	// Redact personal data before it reaches any provider
+	enrichedFields = l.Redactor.Fields(enrichedFields)
+	message = l.Redactor.Text(message)
 
 	switch level {
 	case "info":
@@ -64,7 +170,59 @@ func (l *Logger) Log(ctx context.Context, level, message string, fields map[stri
 		l.Logger.Debug().Fields(enrichedFields).Msg(message)
 	}
 
-	l.AppLogger.Log(ctx, level, message, enrichedFields)
+	if l.AppLogger != nil {
+		l.AppLogger.Log(ctx, level, message, enrichedFields)
+	}
+}
+
+// enabled reports whether any provider takes logs of level. With sinks the
+// lowest sink level decides, and each sink filters by its own level.
+func (l *Logger) enabled(level string) bool {
+	if l.Levels == nil {
+		return true
+	}
+	if multi, ok := l.AppLogger.(*MultiLogger); ok {
+		return parseLogLevel(level) >= multi.minLevel()
+	}
+	return l.Levels.Enabled(level)
+}
+
+// With returns a copy of the Logger that adds fields to every log. The
+// Logger is left untouched, and the fields are redacted like any other.
+func (l *Logger) With(fields map[string]interface{}) *Logger {
+	child := *l
+	child.fields = mergeFields(l.fields, fields)
+	return &child
+}
+
+// Close flushes and releases the provider, if it holds anything
+func (l *Logger) Close(ctx context.Context) error {
+	return closeProvider(ctx, l.AppLogger)
+}
+
+// Describe returns the effective level and the configuration of every
+// provider, including their sampling rules
+func (l *Logger) Describe() LoggerInfo {
+	info := LoggerInfo{}
+	if l.Levels != nil {
+		info.Level = l.Levels.State()
+	}
+
+	if multi, ok := l.AppLogger.(*MultiLogger); ok {
+		info.Providers = multi.Describe()
+	} else if l.AppLogger != nil {
+		info.Providers = []ProviderInfo{describeProvider(l.config, l.sampler)}
+	}
+	return info
+}
+
+func describeProvider(cfg config.LoggerConfig, sampler *Sampler) ProviderInfo {
+	return ProviderInfo{
+		Provider:   cfg.Provider,
+		Format:     cfg.Format,
+		Sampling:   cfg.Sampling,
+		Suppressed: sampler.Suppressed(),
+	}
 }
 
 // enrichWithTraceInfo adds OpenTelemetry trace information to log fields
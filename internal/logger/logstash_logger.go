@@ -2,64 +2,105 @@ package logger
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"net"
+	"os"
 	"time"
 
 	"github.com/your-org/boilerplate-go/internal/config"
 )
 
+// LogstashLogger ships JSON lines to Logstash over TCP, TLS or UDP. Log only
+// queues the entry for the stream writer.
 type LogstashLogger struct {
-	config     config.LoggerConfig
-	connection net.Conn
-	fields     map[string]interface{}
+	*streamWriter
+
+	config config.LoggerConfig
+
+	network   string
+	tlsConfig *tls.Config
 }
 
 func NewLogstashLogger(cfg config.LoggerConfig) *LogstashLogger {
+	if cfg.Url == "" {
+		fmt.Printf("Logstash URL not configured\n")
+		return nil
+	}
+
 	logger := &LogstashLogger{
-		config: cfg,
-		fields: make(map[string]interface{}),
+		config:  cfg,
+		network: "tcp",
 	}
 
-	// Try to establish connection to Logstash
-	if cfg.Url != "" {
-		conn, err := net.Dial("tcp", cfg.Url)
+	switch cfg.Transport {
+	case "udp":
+		logger.network = "udp"
+	case "tls":
+		tlsConfig, err := logstashTLSConfig(cfg)
 		if err != nil {
-			fmt.Printf("Failed to connect to Logstash at %s: %v\n", cfg.Url, err)
-		} else {
-			logger.connection = conn
+			fmt.Printf("Failed to configure TLS for Logstash: %v\n", err)
+			return nil
 		}
+		logger.tlsConfig = tlsConfig
 	}
 
+	logger.streamWriter = newStreamWriter("logstash", cfg.Url, cfg, logger.dial, frameLine)
+
 	return logger
 }
 
-func (l *LogstashLogger) AddField(key string, value interface{}) {
-	l.fields[key] = value
+func (l *LogstashLogger) dial(dialer *net.Dialer) (net.Conn, error) {
+	if l.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", l.config.Url, l.tlsConfig)
+	}
+	return dialer.Dial(l.network, l.config.Url)
+}
+
+// frameLine terminates a log with a newline
+func frameLine(msg []byte) []byte {
+	return append(msg, '\n')
 }
 
-func (l *LogstashLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
-	// If no connection, skip logging
-	if l.connection == nil {
-		return
+func logstashTLSConfig(cfg config.LoggerConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.TLSSkipVerify,
 	}
 
-	// Merge stored fields with provided fields
-	mergedFields := make(map[string]interface{})
-	for k, v := range l.fields {
-		mergedFields[k] = v
+	if host, _, err := net.SplitHostPort(cfg.Url); err == nil {
+		tlsConfig.ServerName = host
 	}
-	for k, v := range fields {
-		mergedFields[k] = v
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
 	}
 
+	return tlsConfig, nil
+}
+
+// With returns a child logger that adds fields to every log
+func (l *LogstashLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+func (l *LogstashLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
 	// Create log entry
 	logEntry := map[string]interface{}{
-		"@timestamp": time.Now().UTC().Format(time.RFC3339),
+		"@timestamp": logTime(ctx).UTC().Format(time.RFC3339),
 		"level":      level,
 		"message":    message,
-		"fields":     mergedFields,
+		"fields":     fields,
 	}
 
 	// Convert to JSON
@@ -69,16 +110,5 @@ func (l *LogstashLogger) Log(ctx context.Context, level, message string, fields
 		return
 	}
 
-	// Send to Logstash
-	_, err = l.connection.Write(append(jsonData, '\n'))
-	if err != nil {
-		fmt.Printf("Failed to send log to Logstash: %v\n", err)
-	}
-}
-
-func (l *LogstashLogger) Close() error {
-	if l.connection != nil {
-		return l.connection.Close()
-	}
-	return nil
+	l.enqueue(jsonData)
 }
@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// logstashServer is a line-based TCP listener that collects the messages it
+// receives
+type logstashServer struct {
+	listener net.Listener
+	messages chan string
+	mu       sync.Mutex
+	conns    []net.Conn
+}
+
+func newLogstashServer(t *testing.T, listener net.Listener) *logstashServer {
+	s := &logstashServer{listener: listener, messages: make(chan string, 100)}
+	go s.accept()
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *logstashServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		go func() {
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				var entry struct {
+					Message string `json:"message"`
+				}
+				json.Unmarshal(scanner.Bytes(), &entry)
+				s.messages <- entry.Message
+			}
+		}()
+	}
+}
+
+// dropConnections closes the accepted connections but keeps listening
+func (s *logstashServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *logstashServer) close() {
+	s.listener.Close()
+	s.dropConnections()
+}
+
+func (s *logstashServer) expect(t *testing.T, message string) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case got := <-s.messages:
+			if got == message {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Expected Logstash to receive %q", message)
+		}
+	}
+}
+
+func TestLogstashLoggerConnectsWhenLogstashComesUp(t *testing.T) {
+	// Reserve an address nobody listens on yet
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	l := NewLogstashLogger(config.LoggerConfig{Url: addr, ReconnectMaxBackoff: 50 * time.Millisecond})
+	defer l.Close(context.Background())
+
+	l.Log(context.Background(), "info", "queued while down", nil)
+	time.Sleep(100 * time.Millisecond)
+
+	listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("Address was taken meanwhile: %v", err)
+	}
+	server := newLogstashServer(t, listener)
+
+	server.expect(t, "queued while down")
+}
+
+func TestLogstashLoggerReconnectsAfterDrop(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newLogstashServer(t, listener)
+
+	l := NewLogstashLogger(config.LoggerConfig{Url: listener.Addr().String(), ReconnectMaxBackoff: 50 * time.Millisecond})
+	defer l.Close(context.Background())
+
+	l.Log(context.Background(), "info", "first", nil)
+	server.expect(t, "first")
+
+	server.dropConnections()
+
+	// Writes right after the drop may still be accepted by the local socket,
+	// so keep logging until one arrives on the new connection
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 0; ; i++ {
+		message := fmt.Sprintf("after drop %d", i)
+		l.Log(context.Background(), "info", message, nil)
+
+		select {
+		case <-server.messages:
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the logger to reconnect")
+		}
+	}
+}
+
+func TestLogstashLoggerConcurrentLogs(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newLogstashServer(t, listener)
+
+	l := NewLogstashLogger(config.LoggerConfig{Url: listener.Addr().String()})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				l.Log(context.Background(), "info", fmt.Sprintf("%d-%d", i, j), nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	received := make(map[string]bool)
+	timeout := time.After(5 * time.Second)
+	for len(received) < 50 {
+		select {
+		case message := <-server.messages:
+			received[message] = true
+		case <-timeout:
+			t.Fatalf("Expected 50 intact lines, got %d", len(received))
+		}
+	}
+}
+
+func TestLogstashLoggerOverTLS(t *testing.T) {
+	// Borrow the test certificate of httptest
+	tlsServer := httptest.NewTLSServer(nil)
+	certificate := tlsServer.TLS.Certificates[0]
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw}), 0600)
+	tlsServer.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{certificate}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newLogstashServer(t, listener)
+
+	l := NewLogstashLogger(config.LoggerConfig{
+		Url:       listener.Addr().String(),
+		Transport: "tls",
+		TLSCAFile: caFile,
+	})
+	if l == nil {
+		t.Fatal("Expected the logger to be created")
+	}
+	defer l.Close(context.Background())
+
+	l.Log(context.Background(), "info", "encrypted", nil)
+	server.expect(t, "encrypted")
+}
+
+func TestLogstashLoggerOverUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	l := NewLogstashLogger(config.LoggerConfig{Url: conn.LocalAddr().String(), Transport: "udp"})
+	defer l.Close(context.Background())
+
+	l.Log(context.Background(), "warn", "datagram", map[string]interface{}{"user_id": 7})
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64*1024)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Expected a datagram, got %v", err)
+	}
+
+	var entry struct {
+		Level   string                 `json:"level"`
+		Message string                 `json:"message"`
+		Fields  map[string]interface{} `json:"fields"`
+	}
+	if err := json.Unmarshal(buf[:n], &entry); err != nil {
+		t.Fatalf("Expected one JSON entry per datagram, got %v", err)
+	}
+	if entry.Level != "warn" || entry.Message != "datagram" || entry.Fields["user_id"] != float64(7) {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+}
+
+func TestLogstashLoggerCloseGivesUpAfterDeadline(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	l := NewLogstashLogger(config.LoggerConfig{Url: addr})
+	l.Log(context.Background(), "info", "never sent", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := l.Close(ctx); err == nil {
+		t.Error("Expected an error for unsent logs")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Close should give up at the deadline, took %s", elapsed)
+	}
+
+	l.Log(context.Background(), "info", "after close", nil)
+	if l.Dropped() != 1 {
+		t.Errorf("Expected logs after close to be dropped, got %d", l.Dropped())
+	}
+}
@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// LokiLogger ships logs to Grafana Loki. Every log becomes a JSON line in
+// the stream of its labels: the configured static labels plus the fields
+// listed in LabelFields, which are left out of the line. Log only queues
+// the entry; a pusher sends batches in the background.
+type LokiLogger struct {
+	config      config.LoggerConfig
+	labels      map[string]string
+	labelFields []string
+	pusher      *lokiPusher
+}
+
+func NewLokiLogger(cfg config.LoggerConfig) *LokiLogger {
+	if cfg.Url == "" {
+		fmt.Printf("Loki URL not configured\n")
+		return nil
+	}
+
+	var useJSON bool
+	switch cfg.Encoding {
+	case "", "protobuf":
+	case "json":
+		useJSON = true
+	default:
+		fmt.Printf("Unknown Loki encoding: %s\n", cfg.Encoding)
+		return nil
+	}
+
+	labels := make(map[string]string, len(cfg.Labels))
+	for name, value := range cfg.Labels {
+		labels[lokiLabelName(name)] = value
+	}
+
+	return &LokiLogger{
+		config:      cfg,
+		labels:      labels,
+		labelFields: cfg.LabelFields,
+		pusher: newLokiPusher(pushConfig{
+			url:           strings.TrimSuffix(cfg.Url, "/"),
+			tenantID:      cfg.TenantID,
+			username:      cfg.Username,
+			password:      cfg.Password,
+			json:          useJSON,
+			flushEntries:  cfg.BulkSize,
+			flushBytes:    cfg.BulkFlushBytes,
+			flushInterval: cfg.FlushInterval,
+			queueSize:     cfg.QueueSize,
+			maxRetries:    cfg.MaxRetries,
+		}),
+	}
+}
+
+func (l *LokiLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	labelSet := make(map[string]string, len(l.labels)+len(l.labelFields))
+	for name, value := range l.labels {
+		labelSet[name] = value
+	}
+
+	line := make(map[string]interface{}, len(fields)+2)
+	line["level"] = level
+	line["message"] = message
+	for k, v := range fields {
+		line[k] = v
+	}
+
+	for _, field := range l.labelFields {
+		if field == "level" {
+			labelSet["level"] = level
+			delete(line, "level")
+			continue
+		}
+		if value, ok := fields[field]; ok {
+			labelSet[lokiLabelName(field)] = fmt.Sprint(value)
+			delete(line, field)
+		}
+	}
+
+	lineBytes, err := json.Marshal(line)
+	if err != nil {
+		fmt.Printf("Failed to marshal log line: %v\n", err)
+		return
+	}
+
+	l.pusher.add(lokiEntry{
+		labels:    lokiLabelString(labelSet),
+		labelSet:  labelSet,
+		timestamp: logTime(ctx),
+		line:      string(lineBytes),
+	})
+}
+
+// With returns a child logger that adds fields to every log
+func (l *LokiLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+// Flush pushes the queued entries and waits for Loki to answer
+func (l *LokiLogger) Flush(ctx context.Context) error {
+	return l.pusher.flush(ctx)
+}
+
+// Close stops accepting entries and pushes the ones still queued
+func (l *LokiLogger) Close(ctx context.Context) error {
+	err := l.pusher.close(ctx)
+
+	if stats := l.pusher.stats(); stats.Failed > 0 || stats.Dropped > 0 {
+		fmt.Printf("Loki logger closed: %d sent, %d failed, %d dropped\n", stats.Sent, stats.Failed, stats.Dropped)
+	}
+	return err
+}
+
+// Stats reports how many entries were sent, failed or dropped
+func (l *LokiLogger) Stats() PushStats {
+	return l.pusher.stats()
+}
+
+// lokiLabelString renders a label set in the Prometheus format Loki expects,
+// e.g. {environment="production", service="api"}
+func lokiLabelString(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// lokiLabelName replaces the characters Loki does not accept in label names
+func lokiLabelName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9')
+		if !valid {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
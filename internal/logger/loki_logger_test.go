@@ -0,0 +1,322 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/your-org/boilerplate-go/internal/config"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type lokiPush struct {
+	contentType string
+	tenant      string
+	username    string
+	password    string
+	// streams maps the label string of every stream to its lines
+	streams map[string][]string
+}
+
+// fakeLoki is an httptest stand-in for the Loki push API. status picks the
+// answer to every request; it sees how many requests came before.
+type fakeLoki struct {
+	mu       sync.Mutex
+	requests int
+	pushes   []lokiPush
+	status   func(request int) int
+}
+
+func newFakeLoki(t *testing.T, status func(request int) int) (*fakeLoki, *httptest.Server) {
+	fake := &fakeLoki{status: status}
+	server := httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeLoki) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != lokiPushPath || r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	request := f.requests
+	f.requests++
+	if f.status != nil {
+		if status := f.status(request); status >= 300 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	push := lokiPush{contentType: r.Header.Get("Content-Type"), tenant: r.Header.Get("X-Scope-OrgID")}
+	push.username, push.password, _ = r.BasicAuth()
+
+	var err error
+	if push.contentType == "application/json" {
+		push.streams, err = decodeLokiJSON(body)
+	} else {
+		push.streams, err = decodeLokiProto(body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.pushes = append(f.pushes, push)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeLoki) snapshot() (int, []lokiPush) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests, append([]lokiPush(nil), f.pushes...)
+}
+
+func decodeLokiJSON(body []byte) (map[string][]string, error) {
+	var req struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, err
+	}
+
+	streams := make(map[string][]string)
+	for _, stream := range req.Streams {
+		labels := lokiLabelString(stream.Stream)
+		for _, value := range stream.Values {
+			if _, err := strconv.ParseInt(value[0], 10, 64); err != nil {
+				return nil, err
+			}
+			streams[labels] = append(streams[labels], value[1])
+		}
+	}
+	return streams, nil
+}
+
+func decodeLokiProto(body []byte) (map[string][]string, error) {
+	raw, err := snappy.Decode(nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := make(map[string][]string)
+	err = walkProto(raw, func(num protowire.Number, stream []byte) error {
+		var labels string
+		var lines []string
+		err := walkProto(stream, func(num protowire.Number, value []byte) error {
+			switch num {
+			case 1:
+				labels = string(value)
+			case 2:
+				return walkProto(value, func(num protowire.Number, value []byte) error {
+					if num == 2 {
+						lines = append(lines, string(value))
+					}
+					return nil
+				})
+			}
+			return nil
+		})
+		streams[labels] = append(streams[labels], lines...)
+		return err
+	})
+	return streams, err
+}
+
+// walkProto calls fn with every length-delimited field of a message
+func walkProto(b []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if err := fn(num, value); err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func newTestLokiLogger(url string, cfg config.LoggerConfig) *LokiLogger {
+	cfg.Url = url
+	cfg.Labels = map[string]string{"service": "api", "environment": "test"}
+	if cfg.LabelFields == nil {
+		cfg.LabelFields = []string{"level"}
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Hour
+	}
+	return NewLokiLogger(cfg)
+}
+
+func TestLokiLoggerPushesStreamsAsProtobuf(t *testing.T) {
+	fake, server := newFakeLoki(t, nil)
+	l := newTestLokiLogger(server.URL, config.LoggerConfig{TenantID: "team-a", Username: "loki", Password: "secret"})
+
+	l.Log(context.Background(), "info", "first", map[string]interface{}{"user_id": 1})
+	l.Log(context.Background(), "error", "failure", nil)
+	l.Log(context.Background(), "info", "second", nil)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, pushes := fake.snapshot()
+	if len(pushes) != 1 {
+		t.Fatalf("Expected 1 push, got %d", len(pushes))
+	}
+	push := pushes[0]
+	if push.contentType != "application/x-protobuf" || push.tenant != "team-a" || push.username != "loki" || push.password != "secret" {
+		t.Errorf("Unexpected request headers: %+v", push)
+	}
+
+	info := push.streams[`{environment="test", level="info", service="api"}`]
+	errs := push.streams[`{environment="test", level="error", service="api"}`]
+	if len(push.streams) != 2 || len(info) != 2 || len(errs) != 1 {
+		t.Fatalf("Expected one stream per level, got %v", push.streams)
+	}
+	if info[0] != `{"message":"first","user_id":1}` || info[1] != `{"message":"second"}` {
+		t.Errorf("Expected the lines in order without the label fields, got %v", info)
+	}
+	if stats := l.Stats(); stats.Sent != 3 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLokiLoggerPushesJSONWithFieldLabels(t *testing.T) {
+	fake, server := newFakeLoki(t, nil)
+	l := newTestLokiLogger(server.URL, config.LoggerConfig{Encoding: "json", LabelFields: []string{"component"}})
+
+	l.Log(context.Background(), "warn", "slow", map[string]interface{}{"component": "db", "ms": 1200})
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, pushes := fake.snapshot()
+	if len(pushes) != 1 || pushes[0].contentType != "application/json" {
+		t.Fatalf("Expected 1 JSON push, got %+v", pushes)
+	}
+	lines := pushes[0].streams[`{component="db", environment="test", service="api"}`]
+	if len(lines) != 1 || lines[0] != `{"level":"warn","message":"slow","ms":1200}` {
+		t.Errorf("Expected the field promoted to a label, got %v", pushes[0].streams)
+	}
+}
+
+func TestLokiLoggerRetriesRetryableStatuses(t *testing.T) {
+	fake, server := newFakeLoki(t, func(request int) int {
+		if request < 2 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusNoContent
+	})
+	l := newTestLokiLogger(server.URL, config.LoggerConfig{MaxRetries: 3})
+
+	l.Log(context.Background(), "info", "eventually", nil)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	requests, pushes := fake.snapshot()
+	if requests != 3 || len(pushes) != 1 {
+		t.Errorf("Expected 2 retries before the push went through, got %d requests", requests)
+	}
+	if stats := l.Stats(); stats.Sent != 1 || stats.Retried != 2 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLokiLoggerDoesNotRetryRejectedPushes(t *testing.T) {
+	fake, server := newFakeLoki(t, func(request int) int { return http.StatusBadRequest })
+	l := newTestLokiLogger(server.URL, config.LoggerConfig{MaxRetries: 3})
+
+	l.Log(context.Background(), "info", "too old", nil)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if requests, _ := fake.snapshot(); requests != 1 {
+		t.Errorf("Expected a single request, got %d", requests)
+	}
+	if stats := l.Stats(); stats.Failed != 1 || stats.Retried != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestLokiLoggerBatchesBySize(t *testing.T) {
+	fake, server := newFakeLoki(t, nil)
+	l := newTestLokiLogger(server.URL, config.LoggerConfig{BulkSize: 2})
+
+	for i := 0; i < 5; i++ {
+		l.Log(context.Background(), "info", strconv.Itoa(i), nil)
+	}
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if requests, _ := fake.snapshot(); requests != 3 {
+		t.Errorf("Expected 3 requests of at most 2 entries, got %d", requests)
+	}
+}
+
+func TestInitLoggerLokiProvider(t *testing.T) {
+	_, server := newFakeLoki(t, nil)
+	appLogger := InitLogger(config.LoggerConfig{Level: "info", Provider: "loki", Url: server.URL})
+	defer appLogger.Close(context.Background())
+
+	if _, ok := appLogger.AppLogger.(*LokiLogger); !ok {
+		t.Errorf("Expected a Loki provider, got %T", appLogger.AppLogger)
+	}
+}
+
+func TestLokiLoggerCloseCutsRetryBackoff(t *testing.T) {
+	_, server := newFakeLoki(t, func(request int) int { return http.StatusServiceUnavailable })
+	l := newTestLokiLogger(server.URL, config.LoggerConfig{MaxRetries: 100})
+
+	l.Log(context.Background(), "info", "never accepted", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := l.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected close to honor its context, took %s", elapsed)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for l.Stats().Failed != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the abandoned entry to be counted as failed, got %+v", l.Stats())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,272 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	lokiPushPath       = "/loki/api/v1/push"
+	lokiRequestTimeout = 10 * time.Second
+	lokiMinBackoff     = 100 * time.Millisecond
+	lokiMaxBackoff     = 5 * time.Second
+)
+
+// PushStats reports the outcome of the entries handed to the Loki pusher
+type PushStats struct {
+	Sent    uint64 `json:"sent"`
+	Failed  uint64 `json:"failed"`
+	Dropped uint64 `json:"dropped"`
+	Retried uint64 `json:"retried"`
+}
+
+type pushConfig struct {
+	url           string
+	tenantID      string
+	username      string
+	password      string
+	json          bool
+	flushEntries  int
+	flushBytes    int
+	flushInterval time.Duration
+	queueSize     int
+	maxRetries    int
+}
+
+type lokiEntry struct {
+	labels    string
+	labelSet  map[string]string
+	timestamp time.Time
+	line      string
+}
+
+type lokiStream struct {
+	labels   string
+	labelSet map[string]string
+	entries  []lokiEntry
+}
+
+// lokiPusher ships entries to the Loki push API from a batchLoop, so
+// logging never blocks on Loki. A batch is pushed as one request, grouped
+// in streams by label set, and retried with backoff while Loki answers
+// with a retryable status.
+type lokiPusher struct {
+	client *http.Client
+	config pushConfig
+	loop   *batchLoop[lokiEntry]
+
+	sent    atomic.Uint64
+	failed  atomic.Uint64
+	retried atomic.Uint64
+}
+
+func newLokiPusher(cfg pushConfig) *lokiPusher {
+	if cfg.flushEntries <= 0 {
+		cfg.flushEntries = 500
+	}
+	if cfg.maxRetries < 0 {
+		cfg.maxRetries = 0
+	}
+
+	p := &lokiPusher{
+		client: &http.Client{Timeout: lokiRequestTimeout},
+		config: cfg,
+	}
+	p.loop = newBatchLoop(batchConfig{
+		flushItems:    cfg.flushEntries,
+		flushBytes:    cfg.flushBytes,
+		flushInterval: cfg.flushInterval,
+		queueSize:     cfg.queueSize,
+	}, func(entry lokiEntry) int { return len(entry.line) }, p.send)
+	p.loop.start()
+	return p
+}
+
+// add queues an entry without blocking and reports whether it was accepted
+func (p *lokiPusher) add(entry lokiEntry) bool {
+	return p.loop.add(entry)
+}
+
+// flush pushes everything queued so far and waits for the request to finish
+func (p *lokiPusher) flush(ctx context.Context) error {
+	return p.loop.flush(ctx)
+}
+
+// close stops accepting entries and pushes the queue. Retries still waiting
+// once ctx is done are given up.
+func (p *lokiPusher) close(ctx context.Context) error {
+	if err := p.loop.close(ctx); err != nil {
+		return fmt.Errorf("loki pusher did not finish pushing: %w", err)
+	}
+	return nil
+}
+
+func (p *lokiPusher) stats() PushStats {
+	return PushStats{
+		Sent:    p.sent.Load(),
+		Failed:  p.failed.Load(),
+		Dropped: p.loop.dropped.Load(),
+		Retried: p.retried.Load(),
+	}
+}
+
+// send pushes the pending batch in requests of at most flushEntries entries
+func (p *lokiPusher) send(pending []lokiEntry) {
+	chunks(pending, p.config.flushEntries, p.push)
+}
+
+// push sends batch, retrying with backoff while the failure is retryable
+func (p *lokiPusher) push(batch []lokiEntry) {
+	body, contentType, err := p.encode(groupStreams(batch))
+	if err != nil {
+		fmt.Printf("Failed to encode logs for Loki: %v\n", err)
+		p.failed.Add(uint64(len(batch)))
+		return
+	}
+
+	backoff := lokiMinBackoff
+	for attempt := 0; ; attempt++ {
+		status, err := p.post(body, contentType)
+		if err == nil && status < 300 {
+			p.sent.Add(uint64(len(batch)))
+			return
+		}
+
+		if err != nil {
+			fmt.Printf("Failed to push logs to Loki: %v\n", err)
+		} else {
+			fmt.Printf("Loki push request failed with status %d\n", status)
+		}
+
+		if (err == nil && !retryable(status)) || attempt >= p.config.maxRetries {
+			p.failed.Add(uint64(len(batch)))
+			return
+		}
+
+		p.retried.Add(uint64(len(batch)))
+		if !p.loop.wait(backoff) {
+			p.failed.Add(uint64(len(batch)))
+			return
+		}
+		backoff = min(backoff*2, lokiMaxBackoff)
+	}
+}
+
+func (p *lokiPusher) post(body []byte, contentType string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, p.config.url+lokiPushPath, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.config.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", p.config.tenantID)
+	}
+	if p.config.username != "" {
+		req.SetBasicAuth(p.config.username, p.config.password)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, nil
+}
+
+// groupStreams groups entries by label set, keeping each stream in time
+// order
+func groupStreams(batch []lokiEntry) []lokiStream {
+	index := make(map[string]int)
+	var streams []lokiStream
+	for _, entry := range batch {
+		i, ok := index[entry.labels]
+		if !ok {
+			i = len(streams)
+			index[entry.labels] = i
+			streams = append(streams, lokiStream{labels: entry.labels, labelSet: entry.labelSet})
+		}
+		streams[i].entries = append(streams[i].entries, entry)
+	}
+
+	for _, stream := range streams {
+		sort.SliceStable(stream.entries, func(a, b int) bool {
+			return stream.entries[a].timestamp.Before(stream.entries[b].timestamp)
+		})
+	}
+	return streams
+}
+
+func (p *lokiPusher) encode(streams []lokiStream) ([]byte, string, error) {
+	if p.config.json {
+		body, err := encodeLokiJSON(streams)
+		return body, "application/json", err
+	}
+	return snappy.Encode(nil, encodeLokiProto(streams)), "application/x-protobuf", nil
+}
+
+// encodeLokiJSON encodes a push request in the JSON format of the push API
+func encodeLokiJSON(streams []lokiStream) ([]byte, error) {
+	type jsonStream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+
+	req := struct {
+		Streams []jsonStream `json:"streams"`
+	}{Streams: make([]jsonStream, 0, len(streams))}
+
+	for _, stream := range streams {
+		values := make([][2]string, len(stream.entries))
+		for i, entry := range stream.entries {
+			values[i] = [2]string{strconv.FormatInt(entry.timestamp.UnixNano(), 10), entry.line}
+		}
+		req.Streams = append(req.Streams, jsonStream{Stream: stream.labelSet, Values: values})
+	}
+	return json.Marshal(req)
+}
+
+// encodeLokiProto encodes a logproto.PushRequest:
+//
+//	PushRequest  { repeated Stream streams = 1; }
+//	Stream       { string labels = 1; repeated Entry entries = 2; }
+//	Entry        { google.protobuf.Timestamp timestamp = 1; string line = 2; }
+func encodeLokiProto(streams []lokiStream) []byte {
+	var req []byte
+	for _, stream := range streams {
+		var s []byte
+		s = protowire.AppendTag(s, 1, protowire.BytesType)
+		s = protowire.AppendString(s, stream.labels)
+		for _, entry := range stream.entries {
+			var ts []byte
+			ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+			ts = protowire.AppendVarint(ts, uint64(entry.timestamp.Unix()))
+			ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+			ts = protowire.AppendVarint(ts, uint64(entry.timestamp.Nanosecond()))
+
+			var e []byte
+			e = protowire.AppendTag(e, 1, protowire.BytesType)
+			e = protowire.AppendBytes(e, ts)
+			e = protowire.AppendTag(e, 2, protowire.BytesType)
+			e = protowire.AppendString(e, entry.line)
+
+			s = protowire.AppendTag(s, 2, protowire.BytesType)
+			s = protowire.AppendBytes(s, e)
+		}
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, s)
+	}
+	return req
+}
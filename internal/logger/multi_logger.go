@@ -0,0 +1,292 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// MultiLogger fans every log out to several sinks. Each sink has its own
+// minimum level and field filters and ships logs from its own goroutine
+// through a bounded queue, so a slow or failing sink drops its own logs
+// instead of blocking or breaking the others.
+type MultiLogger struct {
+	sinks []*sink
+	// levels is the Logger level, which sinks without a level follow and
+	// whose runtime override applies to every sink
+	levels *LevelController
+}
+
+type sinkEntry struct {
+	ctx     context.Context
+	level   string
+	message string
+	fields  map[string]interface{}
+}
+
+type sink struct {
+	name    string
+	config  config.LoggerConfig
+	logger  AppLogger
+	sampler *Sampler
+	// level is the sink level, or TraceLevel to follow the Logger level
+	level   zerolog.Level
+	include map[string]bool
+	exclude map[string]bool
+	queue   chan sinkEntry
+	done    chan struct{}
+	closed  bool
+	mu      sync.RWMutex
+	dropped atomic.Uint64
+}
+
+// NewMultiLogger creates a sink for every entry of cfg.Sinks. Options a sink
+// leaves unset are taken from cfg. A sink level replaces the Logger level,
+// so a sink can be looser or stricter; sinks without one follow the Logger
+// level. Sinks whose provider fails to start are skipped.
+func NewMultiLogger(cfg config.LoggerConfig, opts ...Option) *MultiLogger {
+	m := &MultiLogger{}
+	o := newOptions(opts)
+
+	for i, sinkCfg := range cfg.Sinks {
+		sinkCfg = inheritSinkConfig(cfg, sinkCfg)
+
+		appLogger := newProvider(sinkCfg, o)
+		if appLogger == nil {
+			log.Error().Int("sink", i).Str("provider", sinkCfg.Provider).Msg("Failed to start log sink")
+			continue
+		}
+		s := newSink(fmt.Sprintf("%d:%s", i, sinkCfg.Provider), appLogger, sinkCfg)
+		if cfg.Sinks[i].Level == "" {
+			s.level = zerolog.TraceLevel
+		}
+		m.sinks = append(m.sinks, s)
+	}
+
+	return m
+}
+
+func newSink(name string, appLogger AppLogger, cfg config.LoggerConfig) *sink {
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 10000
+	}
+
+	s := &sink{
+		name:    name,
+		config:  cfg,
+		logger:  appLogger,
+		sampler: NewSampler(cfg.Sampling),
+		level:   parseLogLevel(cfg.Level),
+		include: fieldSet(cfg.IncludeFields),
+		exclude: fieldSet(cfg.ExcludeFields),
+		queue:   make(chan sinkEntry, queueSize),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// connectionKeys address and authenticate a provider. A sink only inherits
+// them from a top-level configuration of the same provider, so that a syslog
+// sink never dials the Elasticsearch URL.
+var connectionKeys = map[string]bool{
+	"url":       true,
+	"transport": true,
+	"username":  true,
+	"password":  true,
+	"api_key":   true,
+}
+
+// inheritSinkConfig fills the options a sink leaves unset with the top-level
+// logger options. With SetKeys an option is unset when its key is missing,
+// so a sink can set false, 0 or ""; without it zero options are unset.
+func inheritSinkConfig(parent, sinkCfg config.LoggerConfig) config.LoggerConfig {
+	parent.Sinks = nil
+	sameProvider := sinkCfg.Provider == "" || sinkCfg.Provider == parent.Provider
+
+	p := reflect.ValueOf(parent)
+	s := reflect.ValueOf(&sinkCfg).Elem()
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "-" || (connectionKeys[key] && !sameProvider) {
+			continue
+		}
+
+		set := !s.Field(i).IsZero()
+		if sinkCfg.SetKeys != nil {
+			set = sinkCfg.SetKeys[key]
+		}
+		if !set {
+			s.Field(i).Set(p.Field(i))
+		}
+	}
+	return sinkCfg
+}
+
+func fieldSet(keys []string) map[string]bool {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		set[key] = true
+	}
+	return set
+}
+
+// With returns a child logger that adds fields to the logs of every sink
+func (m *MultiLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(m, fields)
+}
+
+func (m *MultiLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	parsed := parseLogLevel(level)
+	base, override := m.baseLevel()
+	for _, s := range m.sinks {
+		if parsed < s.minLevel(base, override) {
+			continue
+		}
+		s.offer(ctx, level, message, fields)
+	}
+}
+
+// baseLevel returns the Logger level and whether it is a runtime override
+func (m *MultiLogger) baseLevel() (zerolog.Level, bool) {
+	if m.levels == nil {
+		return zerolog.TraceLevel, false
+	}
+	if level, ok := m.levels.Override(); ok {
+		return level, true
+	}
+	return m.levels.Level(), false
+}
+
+// minLevel returns the lowest level any sink takes
+func (m *MultiLogger) minLevel() zerolog.Level {
+	base, override := m.baseLevel()
+	if override || len(m.sinks) == 0 {
+		return base
+	}
+
+	lowest := zerolog.Disabled
+	for _, s := range m.sinks {
+		lowest = min(lowest, s.minLevel(base, override))
+	}
+	return lowest
+}
+
+// Close drains the queue of every sink and closes the sinks
+func (m *MultiLogger) Close(ctx context.Context) error {
+	var errs []error
+	for _, s := range m.sinks {
+		errs = append(errs, s.close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Describe returns the effective configuration of every sink
+func (m *MultiLogger) Describe() []ProviderInfo {
+	providers := make([]ProviderInfo, 0, len(m.sinks))
+	for _, s := range m.sinks {
+		info := describeProvider(s.config, s.sampler)
+		if s.level != zerolog.TraceLevel {
+			info.Level = s.level.String()
+		}
+		info.Dropped = s.dropped.Load()
+		providers = append(providers, info)
+	}
+	return providers
+}
+
+// minLevel returns the level of the sink given the Logger level. A runtime
+// override applies to every sink.
+func (s *sink) minLevel(base zerolog.Level, override bool) zerolog.Level {
+	if override || s.level == zerolog.TraceLevel {
+		return base
+	}
+	return s.level
+}
+
+// offer queues a log the sink accepts without blocking
+func (s *sink) offer(ctx context.Context, level, message string, fields map[string]interface{}) {
+	fields, ok := s.sampler.Sample(level, message, fields)
+	if !ok {
+		return
+	}
+
+	entry := sinkEntry{ctx: ctx, level: level, message: message, fields: s.filter(fields)}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.dropped.Add(1)
+		return
+	}
+
+	select {
+	case s.queue <- entry:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// filter returns the fields the sink is allowed to ship
+func (s *sink) filter(fields map[string]interface{}) map[string]interface{} {
+	filtered := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if s.include != nil && !s.include[k] {
+			continue
+		}
+		if s.exclude[k] {
+			continue
+		}
+		filtered[k] = v
+	}
+	return filtered
+}
+
+func (s *sink) run() {
+	defer close(s.done)
+	for entry := range s.queue {
+		s.deliver(entry)
+	}
+}
+
+// deliver keeps a panicking provider from taking the sink down
+func (s *sink) deliver(entry sinkEntry) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Str("sink", s.name).Interface("panic", r).Msg("Log sink panicked")
+		}
+	}()
+	s.logger.Log(entry.ctx, entry.level, entry.message, entry.fields)
+}
+
+func (s *sink) close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return fmt.Errorf("log sink %s did not drain: %w", s.name, ctx.Err())
+	}
+
+	if dropped := s.dropped.Load(); dropped > 0 {
+		log.Warn().Str("sink", s.name).Uint64("dropped", dropped).Msg("Log sink dropped logs")
+	}
+	return closeProvider(ctx, s.logger)
+}
@@ -0,0 +1,215 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+type logEntry struct {
+	level   string
+	message string
+	fields  map[string]interface{}
+	time    time.Time
+}
+
+// recordingLogger is an AppLogger that keeps what it receives
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+	closed  bool
+}
+
+func (l *recordingLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+func (l *recordingLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, logEntry{level: level, message: message, fields: fields, time: logTime(ctx)})
+}
+
+func (l *recordingLogger) Close(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
+
+func (l *recordingLogger) logged() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]logEntry(nil), l.entries...)
+}
+
+type panickingLogger struct{}
+
+func (l panickingLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+func (panickingLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	panic("sink down")
+}
+
+type blockingLogger struct {
+	release chan struct{}
+}
+
+func (l blockingLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+func (l blockingLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	<-l.release
+}
+
+func TestMultiLoggerAppliesSinkLevelsAndFilters(t *testing.T) {
+	all := &recordingLogger{}
+	errorsOnly := &recordingLogger{}
+	m := &MultiLogger{
+		sinks: []*sink{
+			newSink("all", all, config.LoggerConfig{Level: "debug", IncludeFields: []string{"user_id", "service"}}),
+			newSink("errors", errorsOnly, config.LoggerConfig{Level: "error", ExcludeFields: []string{"password"}}),
+		},
+	}
+	api := m.With(map[string]interface{}{"service": "api"})
+
+	api.Log(context.Background(), "debug", "details", map[string]interface{}{"user_id": 1, "password": "secret"})
+	api.Log(context.Background(), "error", "failure", map[string]interface{}{"user_id": 1, "password": "secret"})
+
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := all.logged(); len(got) != 2 || len(got[0].fields) != 2 || got[0].fields["service"] != "api" {
+		t.Errorf("Expected both logs with only the included fields, got %+v", got)
+	}
+
+	got := errorsOnly.logged()
+	if len(got) != 1 || got[0].message != "failure" {
+		t.Fatalf("Expected only the error, got %+v", got)
+	}
+	if _, ok := got[0].fields["password"]; ok || got[0].fields["user_id"] != 1 {
+		t.Errorf("Expected the excluded field to be removed, got %v", got[0].fields)
+	}
+	if !all.closed || !errorsOnly.closed {
+		t.Error("Expected Close to close every sink")
+	}
+}
+
+func TestMultiLoggerIsolatesFailingSinks(t *testing.T) {
+	healthy := &recordingLogger{}
+	stuck := blockingLogger{release: make(chan struct{})}
+	m := &MultiLogger{
+		sinks: []*sink{
+			newSink("panicking", panickingLogger{}, config.LoggerConfig{}),
+			newSink("stuck", stuck, config.LoggerConfig{QueueSize: 1}),
+			newSink("healthy", healthy, config.LoggerConfig{}),
+		},
+	}
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		m.Log(context.Background(), "info", "message", nil)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Log should not wait for a stuck sink, took %s", elapsed)
+	}
+
+	close(stuck.release)
+	m.Close(context.Background())
+
+	if got := healthy.logged(); len(got) != 10 {
+		t.Errorf("Expected the healthy sink to receive every log, got %d", len(got))
+	}
+	if dropped := m.sinks[1].dropped.Load(); dropped == 0 {
+		t.Error("Expected the stuck sink to drop logs")
+	}
+}
+
+func TestInitLoggerWithSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appLogger := InitLogger(config.LoggerConfig{
+		Level:    "info",
+		Provider: "stdout",
+		Sinks: []config.LoggerConfig{
+			{Provider: "file", Filepath: path, Level: "warn"},
+			{Provider: "stdout", Format: "json"},
+		},
+	})
+
+	multi, ok := appLogger.AppLogger.(*MultiLogger)
+	if !ok || len(multi.sinks) != 2 {
+		t.Fatalf("Expected a MultiLogger with 2 sinks, got %T", appLogger.AppLogger)
+	}
+	if multi.sinks[1].level != zerolog.TraceLevel {
+		t.Errorf("Expected the sink without a level to follow the Logger level, got %s", multi.sinks[1].level)
+	}
+
+	appLogger.LogInfo(context.Background(), "ignored by file")
+	appLogger.LogWarn(context.Background(), "written to file")
+	if err := appLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	content := readFile(t, path)
+	if strings.Contains(content, "ignored by file") || !strings.Contains(content, "written to file") {
+		t.Errorf("Expected only the warning in the file, got %s", content)
+	}
+}
+
+func TestInitLoggerSinkLooserThanLoggerLevel(t *testing.T) {
+	dir := t.TempDir()
+	debugPath := filepath.Join(dir, "debug.log")
+	defaultPath := filepath.Join(dir, "default.log")
+	appLogger := InitLogger(config.LoggerConfig{
+		Level:    "info",
+		Provider: "stdout",
+		Sinks: []config.LoggerConfig{
+			{Provider: "file", Filepath: debugPath, Level: "debug"},
+			{Provider: "file", Filepath: defaultPath},
+		},
+	})
+
+	appLogger.LogDebug(context.Background(), "debug details")
+	appLogger.Levels.Set("error", time.Minute)
+	appLogger.LogWarn(context.Background(), "warning during override")
+	if err := appLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if content := readFile(t, debugPath); !strings.Contains(content, "debug details") || strings.Contains(content, "warning during override") {
+		t.Errorf("Expected the debug sink to get the debug log and the override to apply, got %s", content)
+	}
+	if content := readFile(t, defaultPath); content != "" {
+		t.Errorf("Expected the sink without a level to follow the Logger level, got %s", content)
+	}
+}
+
+func TestInheritSinkConfig(t *testing.T) {
+	parent := config.LoggerConfig{Level: "info", Provider: "file", Compress: true, MaxRetries: 3, Index: "logs"}
+
+	sinkCfg := inheritSinkConfig(parent, config.LoggerConfig{Provider: "elasticsearch", MaxRetries: 5})
+	if !sinkCfg.Compress || sinkCfg.MaxRetries != 5 || sinkCfg.Index != "logs" || sinkCfg.Provider != "elasticsearch" {
+		t.Errorf("Expected the zero options inherited and the others kept, got %+v", sinkCfg)
+	}
+
+	sinkCfg = inheritSinkConfig(parent, config.LoggerConfig{
+		Provider: "file",
+		SetKeys:  map[string]bool{"provider": true, "compress": true, "max_retries": true, "index": true},
+	})
+	if sinkCfg.Compress || sinkCfg.MaxRetries != 0 || sinkCfg.Index != "" {
+		t.Errorf("Expected the zero values set by the sink to override, got %+v", sinkCfg)
+	}
+	if sinkCfg.Level != "info" {
+		t.Errorf("Expected the unset level inherited, got %q", sinkCfg.Level)
+	}
+}
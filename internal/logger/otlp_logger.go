@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// otlpScope names the instrumentation scope of the records
+const otlpScope = "github.com/your-org/boilerplate-go/internal/logger"
+
+// OTLPLogger emits logs through the OpenTelemetry Logs SDK to the same OTLP
+// endpoint as traces and metrics. The endpoint and headers come from the
+// standard OTEL_EXPORTER_OTLP_* variables set by the telemetry package.
+// Records carry the trace and span of the context they are logged with.
+type OTLPLogger struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+// NewOTLPLogger creates an OTLP logger describing the service with res. It
+// returns nil when the exporter cannot be created.
+func NewOTLPLogger(cfg config.LoggerConfig, res *resource.Resource) *OTLPLogger {
+	exporter, err := otlploghttp.New(context.Background())
+	if err != nil {
+		fmt.Printf("Error creating the OTLP log exporter: %s\n", err)
+		return nil
+	}
+
+	var batchOpts []sdklog.BatchProcessorOption
+	if cfg.QueueSize > 0 {
+		batchOpts = append(batchOpts, sdklog.WithMaxQueueSize(cfg.QueueSize))
+	}
+	if cfg.BulkSize > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportMaxBatchSize(cfg.BulkSize))
+	}
+	if cfg.FlushInterval > 0 {
+		batchOpts = append(batchOpts, sdklog.WithExportInterval(cfg.FlushInterval))
+	}
+
+	if res == nil {
+		res = resource.Default()
+	}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, batchOpts...)),
+	)
+
+	return &OTLPLogger{
+		provider: provider,
+		logger:   provider.Logger(otlpScope),
+	}
+}
+
+func (l *OTLPLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	var record otellog.Record
+	record.SetTimestamp(logTime(ctx))
+	record.SetSeverity(otlpSeverity(level))
+	record.SetSeverityText(level)
+	record.SetBody(otellog.StringValue(message))
+
+	for k, v := range fields {
+		// The SDK takes the trace and span from ctx
+		if k == "trace_id" || k == "span_id" || k == "sampled" {
+			continue
+		}
+		record.AddAttributes(otellog.KeyValue{Key: k, Value: otlpValue(v)})
+	}
+
+	l.logger.Emit(ctx, record)
+}
+
+// With returns a child logger that adds fields to every log
+func (l *OTLPLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+// Flush exports the pending records
+func (l *OTLPLogger) Flush(ctx context.Context) error {
+	return l.provider.ForceFlush(ctx)
+}
+
+// Close exports the pending records and shuts the exporter down
+func (l *OTLPLogger) Close(ctx context.Context) error {
+	return l.provider.Shutdown(ctx)
+}
+
+// otlpSeverity maps a level to its OpenTelemetry severity
+func otlpSeverity(level string) otellog.Severity {
+	switch level {
+	case "trace":
+		return otellog.SeverityTrace
+	case "debug":
+		return otellog.SeverityDebug
+	case "info":
+		return otellog.SeverityInfo
+	case "warn":
+		return otellog.SeverityWarn
+	case "error":
+		return otellog.SeverityError
+	case "fatal":
+		return otellog.SeverityFatal
+	case "panic":
+		return otellog.SeverityFatal4
+	}
+	return otellog.SeverityDebug
+}
+
+// otlpUint encodes v as a string when it overflows the int64 OTLP values
+func otlpUint(v uint64) otellog.Value {
+	if v > math.MaxInt64 {
+		return otellog.StringValue(strconv.FormatUint(v, 10))
+	}
+	return otellog.Int64Value(int64(v))
+}
+
+// otlpValue converts a field value to an attribute value, keeping numbers,
+// booleans, slices and maps typed
+func otlpValue(value interface{}) otellog.Value {
+	switch v := value.(type) {
+	case nil:
+		return otellog.Value{}
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case int:
+		return otellog.IntValue(v)
+	case int32:
+		return otellog.Int64Value(int64(v))
+	case int64:
+		return otellog.Int64Value(v)
+	case uint:
+		return otlpUint(uint64(v))
+	case uint32:
+		return otellog.Int64Value(int64(v))
+	case uint64:
+		return otlpUint(v)
+	case float32:
+		return otellog.Float64Value(float64(v))
+	case float64:
+		return otellog.Float64Value(v)
+	case []byte:
+		return otellog.BytesValue(v)
+	case error:
+		return otellog.StringValue(v.Error())
+	case time.Time:
+		return otellog.StringValue(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return otellog.StringValue(v.String())
+	case fmt.Stringer:
+		return otellog.StringValue(v.String())
+	case []string:
+		values := make([]otellog.Value, len(v))
+		for i, s := range v {
+			values[i] = otellog.StringValue(s)
+		}
+		return otellog.SliceValue(values...)
+	case []interface{}:
+		values := make([]otellog.Value, len(v))
+		for i, item := range v {
+			values[i] = otlpValue(item)
+		}
+		return otellog.SliceValue(values...)
+	case map[string]interface{}:
+		kvs := make([]otellog.KeyValue, 0, len(v))
+		for key, item := range v {
+			kvs = append(kvs, otellog.KeyValue{Key: key, Value: otlpValue(item)})
+		}
+		return otellog.MapValue(kvs...)
+	case map[string]string:
+		kvs := make([]otellog.KeyValue, 0, len(v))
+		for key, item := range v {
+			kvs = append(kvs, otellog.String(key, item))
+		}
+		return otellog.MapValue(kvs...)
+	}
+	return otellog.StringValue(fmt.Sprint(value))
+}
@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeCollector is an OTLP/HTTP receiver that keeps the exported records
+type fakeCollector struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*collogspb.ExportLogsServiceRequest
+}
+
+func newFakeCollector(t *testing.T) *fakeCollector {
+	c := &fakeCollector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		req := &collogspb.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.mu.Lock()
+		c.requests = append(c.requests, req)
+		c.mu.Unlock()
+
+		resp, _ := proto.Marshal(&collogspb.ExportLogsServiceResponse{})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Write(resp)
+	}))
+	t.Cleanup(c.Close)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", c.URL)
+	return c
+}
+
+// records returns the exported records with the resource they came with
+func (c *fakeCollector) records() ([]*logspb.LogRecord, []*commonpb.KeyValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var records []*logspb.LogRecord
+	var resourceAttrs []*commonpb.KeyValue
+	for _, req := range c.requests {
+		for _, rl := range req.ResourceLogs {
+			resourceAttrs = rl.Resource.Attributes
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+	}
+	return records, resourceAttrs
+}
+
+func attr(kvs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+func TestOTLPLoggerExportsRecords(t *testing.T) {
+	collector := newFakeCollector(t)
+
+	res := resource.NewSchemaless(attribute.String("service.name", "boilerplate-go"))
+	l := NewOTLPLogger(config.LoggerConfig{}, res)
+	if l == nil {
+		t.Fatal("Expected the OTLP logger to start")
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "request")
+	l.With(map[string]interface{}{"component": "users"}).Log(ctx, "error", "db down", map[string]interface{}{
+		"attempts": 3,
+		"retry":    true,
+		"trace_id": span.SpanContext().TraceID().String(),
+	})
+	span.End()
+
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error on close, got %v", err)
+	}
+
+	records, resourceAttrs := collector.records()
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+
+	if record.Body.GetStringValue() != "db down" || record.SeverityText != "error" || record.SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_ERROR {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if got := attr(record.Attributes, "attempts").GetIntValue(); got != 3 {
+		t.Errorf("Expected attempts to stay an integer, got %v", attr(record.Attributes, "attempts"))
+	}
+	if !attr(record.Attributes, "retry").GetBoolValue() || attr(record.Attributes, "component").GetStringValue() != "users" {
+		t.Errorf("Expected the fields as attributes, got %v", record.Attributes)
+	}
+	if attr(record.Attributes, "trace_id") != nil {
+		t.Error("Expected trace_id to be carried by the record, not as an attribute")
+	}
+
+	traceID := span.SpanContext().TraceID()
+	spanID := span.SpanContext().SpanID()
+	if string(record.TraceId) != string(traceID[:]) || string(record.SpanId) != string(spanID[:]) {
+		t.Errorf("Expected the record to be correlated with the span, got trace %x span %x", record.TraceId, record.SpanId)
+	}
+
+	if got := attr(resourceAttrs, "service.name").GetStringValue(); got != "boilerplate-go" {
+		t.Errorf("Expected the shared resource, got service.name %q", got)
+	}
+}
+
+func TestInitLoggerOTLPProvider(t *testing.T) {
+	collector := newFakeCollector(t)
+
+	res := resource.NewSchemaless(attribute.String("service.name", "boilerplate-go"))
+	appLogger := InitLogger(config.LoggerConfig{Level: "info", Provider: "otlp"}, WithResource(res))
+	if _, ok := appLogger.AppLogger.(*OTLPLogger); !ok {
+		t.Fatalf("Expected an OTLP provider, got %T", appLogger.AppLogger)
+	}
+
+	appLogger.LogWarn(context.Background(), "slow query")
+	if err := appLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error on close, got %v", err)
+	}
+
+	records, _ := collector.records()
+	if len(records) != 1 || records[0].SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_WARN {
+		t.Errorf("Expected a warning record, got %v", records)
+	}
+}
+
+func TestOTLPValueUnsignedOverflow(t *testing.T) {
+	if v := otlpValue(uint64(math.MaxInt64)); v.Kind() != otellog.KindInt64 || v.AsInt64() != math.MaxInt64 {
+		t.Errorf("Expected MaxInt64 as an int, got %v", v)
+	}
+	if v := otlpValue(uint64(math.MaxUint64)); v.Kind() != otellog.KindString || v.AsString() != "18446744073709551615" {
+		t.Errorf("Expected values above MaxInt64 as strings, got %v", v)
+	}
+}
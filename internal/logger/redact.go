@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactionKeys are the field key patterns redacted unless the
+// configuration lists its own
+var DefaultRedactionKeys = []string{
+	"email",
+	"passw",
+	"secret",
+	"token",
+	"authorization",
+	"cookie",
+	"api_?key",
+	"^name$",
+	"(first|last|full|user|old|new)_?name",
+}
+
+// builtinDetectors find personal data in free text
+var builtinDetectors = []*regexp.Regexp{
+	// Email addresses
+	regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	// Bearer and basic credentials
+	regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`),
+	// JSON web tokens
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`),
+}
+
+// Redactor removes personal data from logs before they reach any provider.
+// Fields whose key matches a pattern are masked or hashed as a whole, and
+// the detectors mask matches inside the message and every string value.
+type Redactor struct {
+	keys      []*regexp.Regexp
+	allow     map[string]bool
+	detectors []*regexp.Regexp
+	hash      bool
+	salt      string
+}
+
+// NewRedactor builds a Redactor from cfg. It returns nil when redaction is
+// disabled; a nil Redactor leaves logs untouched.
+func NewRedactor(cfg config.RedactionConfig) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	r := &Redactor{
+		allow:     make(map[string]bool, len(cfg.Allow)),
+		detectors: append([]*regexp.Regexp(nil), builtinDetectors...),
+		salt:      cfg.HashSalt,
+	}
+
+	switch cfg.Mode {
+	case "", "mask":
+	case "hash":
+		r.hash = true
+	default:
+		return nil, fmt.Errorf("unknown redaction mode %q", cfg.Mode)
+	}
+
+	keys := cfg.Keys
+	if len(keys) == 0 {
+		keys = DefaultRedactionKeys
+	}
+	for _, key := range keys {
+		re, err := regexp.Compile("(?i)" + key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction key pattern %q: %w", key, err)
+		}
+		r.keys = append(r.keys, re)
+	}
+
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.detectors = append(r.detectors, re)
+	}
+
+	for _, key := range cfg.Allow {
+		r.allow[strings.ToLower(key)] = true
+	}
+
+	return r, nil
+}
+
+// Text masks the detected personal data in s
+func (r *Redactor) Text(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, detector := range r.detectors {
+		s = detector.ReplaceAllStringFunc(s, r.replace)
+	}
+	return s
+}
+
+// Fields returns a redacted copy of fields. Nested maps and slices are
+// redacted too.
+func (r *Redactor) Fields(fields map[string]interface{}) map[string]interface{} {
+	if r == nil {
+		return fields
+	}
+
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		redacted[key] = r.field(key, value)
+	}
+	return redacted
+}
+
+func (r *Redactor) field(key string, value interface{}) interface{} {
+	if r.allow[strings.ToLower(key)] {
+		return value
+	}
+	if r.sensitive(key) {
+		return r.replace(fmt.Sprint(value))
+	}
+	return r.value(value)
+}
+
+func (r *Redactor) value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.Text(v)
+	case error:
+		return r.Text(v.Error())
+	case map[string]interface{}:
+		return r.Fields(v)
+	case map[string]string:
+		redacted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			redacted[key] = r.field(key, value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, value := range v {
+			redacted[i] = r.value(value)
+		}
+		return redacted
+	case []string:
+		redacted := make([]string, len(v))
+		for i, value := range v {
+			redacted[i] = r.Text(value)
+		}
+		return redacted
+	}
+	return value
+}
+
+func (r *Redactor) sensitive(key string) bool {
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// replace masks value or, in hash mode, replaces it with a salted hash so
+// that logs can still be correlated by value
+func (r *Redactor) replace(value string) string {
+	if !r.hash {
+		return redactedValue
+	}
+	sum := sha256.Sum256([]byte(r.salt + value))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+func TestRedactorMasksSensitiveKeys(t *testing.T) {
+	r, err := NewRedactor(config.RedactionConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got := r.Fields(map[string]interface{}{
+		"email":         "john@example.com",
+		"name":          "John Doe",
+		"Authorization": "Bearer abc",
+		"db_password":   "hunter2",
+		"user_id":       42,
+		"request": map[string]interface{}{
+			"access_token": "xyz",
+			"path":         "/users",
+		},
+	})
+
+	for _, key := range []string{"email", "name", "Authorization", "db_password"} {
+		if got[key] != redactedValue {
+			t.Errorf("Expected %s to be redacted, got %v", key, got[key])
+		}
+	}
+	if got["user_id"] != 42 {
+		t.Errorf("Expected user_id to be kept, got %v", got["user_id"])
+	}
+
+	nested := got["request"].(map[string]interface{})
+	if nested["access_token"] != redactedValue || nested["path"] != "/users" {
+		t.Errorf("Expected nested fields to be redacted by key, got %v", nested)
+	}
+}
+
+func TestRedactorDetectsPersonalDataInText(t *testing.T) {
+	r, _ := NewRedactor(config.RedactionConfig{
+		Enabled:  true,
+		Patterns: []string{`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b`},
+	})
+
+	message := r.Text("user with email john@example.com already exists, cpf 123.456.789-09")
+	if strings.Contains(message, "john@example.com") || strings.Contains(message, "123.456.789-09") {
+		t.Errorf("Expected the email and the custom pattern to be masked, got %q", message)
+	}
+
+	got := r.Fields(map[string]interface{}{
+		"error":  errors.New("request with Bearer eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.sig failed"),
+		"emails": []string{"a@example.com"},
+	})
+	if strings.Contains(got["error"].(string), "eyJ") {
+		t.Errorf("Expected the credential to be masked, got %v", got["error"])
+	}
+	if got["emails"] != redactedValue {
+		t.Errorf("Expected the emails key to be redacted, got %v", got["emails"])
+	}
+}
+
+func TestRedactorAllowlist(t *testing.T) {
+	r, _ := NewRedactor(config.RedactionConfig{Enabled: true, Allow: []string{"token_count"}})
+
+	got := r.Fields(map[string]interface{}{"token_count": 12, "token": "secret"})
+	if got["token_count"] != 12 || got["token"] != redactedValue {
+		t.Errorf("Expected only the allowed key to be kept, got %v", got)
+	}
+}
+
+func TestRedactorHashMode(t *testing.T) {
+	r, _ := NewRedactor(config.RedactionConfig{Enabled: true, Mode: "hash", HashSalt: "pepper"})
+
+	first := r.Fields(map[string]interface{}{"email": "john@example.com"})["email"].(string)
+	second := r.Fields(map[string]interface{}{"email": "john@example.com"})["email"].(string)
+	other := r.Fields(map[string]interface{}{"email": "jane@example.com"})["email"].(string)
+
+	if !strings.HasPrefix(first, "sha256:") || first != second || first == other {
+		t.Errorf("Expected stable, distinct hashes, got %s, %s and %s", first, second, other)
+	}
+}
+
+func TestRedactorConfigErrors(t *testing.T) {
+	if r, err := NewRedactor(config.RedactionConfig{}); r != nil || err != nil {
+		t.Errorf("Expected a nil Redactor when disabled, got %v, %v", r, err)
+	}
+	if _, err := NewRedactor(config.RedactionConfig{Enabled: true, Mode: "scramble"}); err == nil {
+		t.Error("Expected unknown modes to be rejected")
+	}
+	if _, err := NewRedactor(config.RedactionConfig{Enabled: true, Keys: []string{"("}}); err == nil {
+		t.Error("Expected invalid key patterns to be rejected")
+	}
+}
+
+func TestLoggerRedactsBeforeProviders(t *testing.T) {
+	recorder := &recordingLogger{}
+	redactor, _ := NewRedactor(config.RedactionConfig{Enabled: true})
+	appLogger := Logger{AppLogger: recorder, Redactor: redactor}
+
+	appLogger.LogInfo(context.Background(), "Created john@example.com", map[string]interface{}{"email": "john@example.com"})
+
+	got := recorder.logged()
+	if len(got) != 1 || strings.Contains(got[0].message, "john@") || got[0].fields["email"] != redactedValue {
+		t.Errorf("Expected the provider to receive redacted data, got %+v", got)
+	}
+}
+
+// TestRedactorDefaultsCoverUserService feeds every field key UserService
+// logs, so that a new key holding personal data fails here
+func TestRedactorDefaultsCoverUserService(t *testing.T) {
+	r, err := NewRedactor(config.RedactionConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	personal := map[string]interface{}{
+		"name":      "John Doe",
+		"old_name":  "John Doe",
+		"new_name":  "Johnny Doe",
+		"email":     "john@example.com",
+		"old_email": "john@example.com",
+		"new_email": "johnny@example.com",
+	}
+	kept := map[string]interface{}{
+		"user_id":          42,
+		"existing_id":      7,
+		"event_id":         "evt-1",
+		"event_name":       "user.created",
+		"topic_name":       "user.events",
+		"service_name":     "boilerplate-go",
+		"file_name":        "app.log",
+		"table_name":       "users",
+		"count":            3,
+		"limit":            10,
+		"offset":           0,
+		"duration":         "12ms",
+		"validation_error": "name is required",
+	}
+
+	fields := make(map[string]interface{}, len(personal)+len(kept))
+	for k, v := range personal {
+		fields[k] = v
+	}
+	for k, v := range kept {
+		fields[k] = v
+	}
+
+	got := r.Fields(fields)
+	for key := range personal {
+		if got[key] != redactedValue {
+			t.Errorf("Expected %s to be redacted, got %v", key, got[key])
+		}
+	}
+	for key, want := range kept {
+		if got[key] != want {
+			t.Errorf("Expected %s to be kept, got %v", key, got[key])
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// SuppressedField carries how many duplicates of a log were dropped by
+// sampling since the previous one that went through
+const SuppressedField = "suppressed_duplicates"
+
+// maxSamplerKeys bounds the number of message keys a Sampler tracks
+const maxSamplerKeys = 10000
+
+// Sampler decides which logs a provider ships. Logs are keyed by level and
+// message, and a log must pass every configured rule:
+//   - the rate of its level, a fraction of logs kept at random
+//   - the first First logs of a key in every Period, then every Thereafter-th
+//   - a token bucket per key refilled at RatePerSecond up to Burst
+type Sampler struct {
+	config     config.SamplingConfig
+	now        func() time.Time
+	mu         sync.Mutex
+	keys       map[string]*sampleKey
+	suppressed atomic.Uint64
+}
+
+type sampleKey struct {
+	windowStart time.Time
+	count       int
+	tokens      float64
+	refilledAt  time.Time
+	suppressed  uint64
+}
+
+// NewSampler returns nil when sampling is disabled; a nil Sampler keeps every
+// log
+func NewSampler(cfg config.SamplingConfig) *Sampler {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Period <= 0 {
+		cfg.Period = time.Second
+	}
+	if cfg.RatePerSecond > 0 && cfg.Burst <= 0 {
+		cfg.Burst = 1
+	}
+
+	return &Sampler{
+		config: cfg,
+		now:    time.Now,
+		keys:   make(map[string]*sampleKey),
+	}
+}
+
+// Sample reports whether the log should be shipped. When it should and
+// duplicates were suppressed before it, fields gets a copy that carries
+// their count.
+func (s *Sampler) Sample(level, message string, fields map[string]interface{}) (map[string]interface{}, bool) {
+	if s == nil {
+		return fields, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key := s.key(level+"\x00"+message, now)
+
+	if !s.allow(level, key, now) {
+		key.suppressed++
+		s.suppressed.Add(1)
+		return nil, false
+	}
+
+	if key.suppressed > 0 {
+		withCount := make(map[string]interface{}, len(fields)+1)
+		for k, v := range fields {
+			withCount[k] = v
+		}
+		withCount[SuppressedField] = key.suppressed
+		key.suppressed = 0
+		fields = withCount
+	}
+	return fields, true
+}
+
+// Suppressed reports how many logs were dropped in total
+func (s *Sampler) Suppressed() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.suppressed.Load()
+}
+
+// allow applies the rules in order. Callers must hold s.mu.
+func (s *Sampler) allow(level string, key *sampleKey, now time.Time) bool {
+	if rate, ok := s.config.Rates[level]; ok && rate < 1 && rand.Float64() >= rate {
+		return false
+	}
+
+	if s.config.First > 0 {
+		if now.Sub(key.windowStart) >= s.config.Period {
+			key.windowStart = now
+			key.count = 0
+		}
+		key.count++
+		if key.count > s.config.First {
+			if s.config.Thereafter <= 0 || (key.count-s.config.First)%s.config.Thereafter != 0 {
+				return false
+			}
+		}
+	}
+
+	if s.config.RatePerSecond > 0 {
+		burst := float64(s.config.Burst)
+		key.tokens = min(burst, key.tokens+now.Sub(key.refilledAt).Seconds()*s.config.RatePerSecond)
+		key.refilledAt = now
+		if key.tokens < 1 {
+			return false
+		}
+		key.tokens--
+	}
+
+	return true
+}
+
+// key returns the state of a message key, forgetting idle keys once too
+// many are tracked. Callers must hold s.mu.
+func (s *Sampler) key(name string, now time.Time) *sampleKey {
+	if key, ok := s.keys[name]; ok {
+		return key
+	}
+
+	if len(s.keys) >= maxSamplerKeys {
+		for k, v := range s.keys {
+			if now.Sub(v.windowStart) >= s.config.Period && now.Sub(v.refilledAt) >= s.config.Period {
+				delete(s.keys, k)
+			}
+		}
+		if len(s.keys) >= maxSamplerKeys {
+			s.keys = make(map[string]*sampleKey)
+		}
+	}
+
+	key := &sampleKey{
+		windowStart: now,
+		tokens:      float64(s.config.Burst),
+		refilledAt:  now,
+	}
+	s.keys[name] = key
+	return key
+}
+
+// sampledLogger applies a Sampler in front of a provider
+type sampledLogger struct {
+	AppLogger
+	sampler *Sampler
+}
+
+func (l *sampledLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	if fields, ok := l.sampler.Sample(level, message, fields); ok {
+		l.AppLogger.Log(ctx, level, message, fields)
+	}
+}
+
+// With returns a child logger whose logs are sampled too
+func (l *sampledLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+func (l *sampledLogger) Close(ctx context.Context) error {
+	return closeProvider(ctx, l.AppLogger)
+}
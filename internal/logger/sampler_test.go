@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+func newTestSampler(cfg config.SamplingConfig) (*Sampler, *fakeClock) {
+	cfg.Enabled = true
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := NewSampler(cfg)
+	s.now = clock.now
+	return s, clock
+}
+
+func TestSamplerFirstThenEveryNth(t *testing.T) {
+	s, clock := newTestSampler(config.SamplingConfig{First: 2, Thereafter: 3, Period: time.Minute})
+
+	var kept []int
+	var suppressed []interface{}
+	for i := 1; i <= 8; i++ {
+		if fields, ok := s.Sample("error", "db down", nil); ok {
+			kept = append(kept, i)
+			suppressed = append(suppressed, fields[SuppressedField])
+		}
+	}
+	if len(kept) != 4 || kept[2] != 5 || kept[3] != 8 {
+		t.Fatalf("Expected logs 1, 2, 5 and 8 to be kept, got %v", kept)
+	}
+	if suppressed[0] != nil || suppressed[2] != uint64(2) || suppressed[3] != uint64(2) {
+		t.Errorf("Expected kept logs to carry the suppressed count, got %v", suppressed)
+	}
+
+	// Other messages have their own counters
+	if _, ok := s.Sample("error", "cache down", nil); !ok {
+		t.Error("Expected a different message to be kept")
+	}
+
+	// The window resets after the period
+	clock.advance(time.Minute)
+	if _, ok := s.Sample("error", "db down", nil); !ok {
+		t.Error("Expected the first log of a new period to be kept")
+	}
+	if got := s.Suppressed(); got != 4 {
+		t.Errorf("Expected 4 suppressed logs, got %d", got)
+	}
+}
+
+func TestSamplerTokenBucket(t *testing.T) {
+	s, clock := newTestSampler(config.SamplingConfig{RatePerSecond: 2, Burst: 2})
+
+	kept := 0
+	for i := 0; i < 5; i++ {
+		if _, ok := s.Sample("warn", "slow query", nil); ok {
+			kept++
+		}
+	}
+	if kept != 2 {
+		t.Fatalf("Expected the burst of 2 to be kept, got %d", kept)
+	}
+
+	clock.advance(500 * time.Millisecond)
+	fields, ok := s.Sample("warn", "slow query", map[string]interface{}{"table": "users"})
+	if !ok || fields[SuppressedField] != uint64(3) || fields["table"] != "users" {
+		t.Errorf("Expected a refilled token and the suppressed count, got %v, %v", fields, ok)
+	}
+}
+
+func TestSamplerLevelRates(t *testing.T) {
+	s, _ := newTestSampler(config.SamplingConfig{Rates: map[string]float64{"debug": 0, "info": 1}})
+
+	if _, ok := s.Sample("debug", "noise", nil); ok {
+		t.Error("Expected debug logs to be dropped at rate 0")
+	}
+	if _, ok := s.Sample("info", "noise", nil); !ok {
+		t.Error("Expected info logs to be kept at rate 1")
+	}
+	if _, ok := s.Sample("error", "noise", nil); !ok {
+		t.Error("Expected levels without a rate to be kept")
+	}
+}
+
+func TestInitLoggerSamplingPerProvider(t *testing.T) {
+	appLogger := InitLogger(config.LoggerConfig{
+		Level:    "info",
+		Provider: "stdout",
+		Sampling: config.SamplingConfig{Enabled: true, First: 1, Period: time.Minute},
+		Sinks: []config.LoggerConfig{
+			{Provider: "stdout", Format: "json"},
+			{Provider: "stdout", Format: "json", Level: "error", Sampling: config.SamplingConfig{Enabled: true, First: 5, Period: time.Minute}},
+		},
+	})
+	defer appLogger.Close(context.Background())
+
+	for i := 0; i < 3; i++ {
+		appLogger.LogError(context.Background(), "repeated", nil)
+	}
+
+	info := appLogger.Describe()
+	if len(info.Providers) != 2 || info.Level.Level != "info" {
+		t.Fatalf("Unexpected effective config: %+v", info)
+	}
+	if p := info.Providers[0]; p.Sampling.First != 1 || p.Suppressed != 2 || p.Level != "" {
+		t.Errorf("Expected the first sink to inherit the sampling rules, got %+v", p)
+	}
+	if p := info.Providers[1]; p.Sampling.First != 5 || p.Suppressed != 0 || p.Level != "error" {
+		t.Errorf("Expected the second sink to use its own rules, got %+v", p)
+	}
+}
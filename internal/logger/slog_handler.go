@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogHandler is a slog.Handler that sends records through the Logger, so
+// slog output from other libraries reaches the same providers and gets the
+// same level control, redaction and trace enrichment. Groups become nested
+// field maps.
+type SlogHandler struct {
+	logger *Logger
+	fields map[string]interface{}
+	groups []string
+}
+
+// NewSlogHandler creates a slog.Handler backed by logger
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+// Slog returns a slog.Logger backed by the Logger
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(NewSlogHandler(l))
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	if h.logger.Levels == nil {
+		return true
+	}
+	return h.logger.Levels.Enabled(slogLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !r.Time.IsZero() {
+		ctx = withTime(ctx, r.Time)
+	}
+
+	h.logger.Log(ctx, slogLevel(r.Level), r.Message, h.withAttrs(attrs))
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &SlogHandler{logger: h.logger, fields: h.withAttrs(attrs), groups: h.groups}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := make([]string, len(h.groups), len(h.groups)+1)
+	copy(groups, h.groups)
+	return &SlogHandler{logger: h.logger, fields: h.fields, groups: append(groups, name)}
+}
+
+// withAttrs returns the handler fields with attrs added to the innermost
+// open group. Maps along the group path are copied, the rest is shared, so
+// the fields of h are never modified.
+func (h *SlogHandler) withAttrs(attrs []slog.Attr) map[string]interface{} {
+	added := make(map[string]interface{}, len(attrs))
+	addSlogAttrs(added, attrs)
+	if len(added) == 0 {
+		return h.fields
+	}
+
+	root := copyFields(h.fields)
+	current := root
+	for _, group := range h.groups {
+		child, _ := current[group].(map[string]interface{})
+		child = copyFields(child)
+		current[group] = child
+		current = child
+	}
+	for k, v := range added {
+		current[k] = v
+	}
+	return root
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(fields)+1)
+	for k, v := range fields {
+		copied[k] = v
+	}
+	return copied
+}
+
+// addSlogAttrs adds attrs to fields following the slog rules: empty attrs
+// are dropped, groups without attributes are dropped and groups without a
+// key are inlined
+func addSlogAttrs(fields map[string]interface{}, attrs []slog.Attr) {
+	for _, a := range attrs {
+		value := a.Value.Resolve()
+		if a.Key == "" && value.Kind() != slog.KindGroup {
+			continue
+		}
+
+		if value.Kind() != slog.KindGroup {
+			fields[a.Key] = slogValue(value)
+			continue
+		}
+
+		if a.Key == "" {
+			addSlogAttrs(fields, value.Group())
+			continue
+		}
+		group := make(map[string]interface{})
+		addSlogAttrs(group, value.Group())
+		if len(group) > 0 {
+			fields[a.Key] = group
+		}
+	}
+}
+
+// slogValue converts a resolved value that is not a group to a field value
+func slogValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return v.Time()
+	}
+	return v.Any()
+}
+
+// slogLevel maps a slog level to the closest Logger level. Levels above
+// error stay at error so that slog never terminates the process.
+func slogLevel(level slog.Level) string {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return "info"
+	case level < slog.LevelError:
+		return "warn"
+	}
+	return "error"
+}
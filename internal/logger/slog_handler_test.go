@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+func newSlogTestLogger() (*slog.Logger, *recordingLogger) {
+	recorder := &recordingLogger{}
+	appLogger := &Logger{AppLogger: recorder, Levels: NewLevelController("debug")}
+	return appLogger.Slog(), recorder
+}
+
+func TestSlogHandlerGroupsAndAttrs(t *testing.T) {
+	log, recorder := newSlogTestLogger()
+
+	log.With("service", "users").
+		WithGroup("http").
+		With("method", "GET").
+		WithGroup("response").
+		Info("request served", "status", 200, slog.Group("timing", "total", time.Second))
+
+	got := recorder.logged()
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 log, got %d", len(got))
+	}
+	want := map[string]interface{}{
+		"service": "users",
+		"http": map[string]interface{}{
+			"method": "GET",
+			"response": map[string]interface{}{
+				"status": int64(200),
+				"timing": map[string]interface{}{"total": "1s"},
+			},
+		},
+	}
+	if got[0].level != "info" || got[0].message != "request served" || !reflect.DeepEqual(got[0].fields, want) {
+		t.Errorf("Unexpected log: %+v", got[0])
+	}
+}
+
+func TestSlogHandlerDropsEmptyGroupsAndAttrs(t *testing.T) {
+	log, recorder := newSlogTestLogger()
+
+	log.WithGroup("empty").Warn("no attrs", slog.Attr{}, slog.Group("nothing"), slog.Group("", "inlined", true))
+
+	got := recorder.logged()
+	want := map[string]interface{}{"empty": map[string]interface{}{"inlined": true}}
+	if len(got) != 1 || got[0].level != "warn" || !reflect.DeepEqual(got[0].fields, want) {
+		t.Errorf("Unexpected log: %+v", got)
+	}
+
+	log.WithGroup("empty").Info("nothing at all")
+	if got := recorder.logged(); len(got[1].fields) != 0 {
+		t.Errorf("Expected groups without attributes to be dropped, got %v", got[1].fields)
+	}
+}
+
+func TestSlogHandlerDoesNotShareState(t *testing.T) {
+	log, recorder := newSlogTestLogger()
+
+	base := log.WithGroup("g").With("a", 1)
+	base.With("b", 2).Info("first")
+	base.With("c", 3).Info("second")
+
+	got := recorder.logged()
+	first := got[0].fields["g"].(map[string]interface{})
+	second := got[1].fields["g"].(map[string]interface{})
+	if _, ok := first["c"]; ok || len(first) != 2 {
+		t.Errorf("Expected sibling handlers to be independent, got %v", first)
+	}
+	if _, ok := second["b"]; ok || len(second) != 2 {
+		t.Errorf("Expected sibling handlers to be independent, got %v", second)
+	}
+}
+
+func TestSlogHandlerUsesPipeline(t *testing.T) {
+	recorder := &recordingLogger{}
+	redactor, _ := NewRedactor(config.RedactionConfig{Enabled: true})
+	appLogger := &Logger{AppLogger: recorder, Levels: NewLevelController("info"), Redactor: redactor}
+	log := appLogger.Slog()
+
+	ctx := WithContext(context.Background(), map[string]interface{}{RequestIDField: "req-1"})
+	log.DebugContext(ctx, "hidden")
+	log.ErrorContext(ctx, "login failed", "email", "john@example.com", "err", errors.New("boom"))
+	log.Log(ctx, slog.LevelError+4, "beyond error")
+
+	got := recorder.logged()
+	if len(got) != 2 {
+		t.Fatalf("Expected the debug log to be filtered by level, got %+v", got)
+	}
+	if got[0].fields["email"] != redactedValue || got[0].fields["err"] != "boom" || got[0].fields[RequestIDField] != "req-1" {
+		t.Errorf("Expected redacted fields with the context fields, got %v", got[0].fields)
+	}
+	if got[1].level != "error" {
+		t.Errorf("Expected levels above error to map to error, got %s", got[1].level)
+	}
+}
+
+func TestSlogHandlerUsesRecordTime(t *testing.T) {
+	log, recorder := newSlogTestLogger()
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := slog.NewRecord(created, slog.LevelWarn, "queued", 0)
+	if err := log.Handler().Handle(context.Background(), record); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got := recorder.logged()
+	if len(got) != 1 || !got[0].time.Equal(created) {
+		t.Fatalf("Expected the record time %s, got %+v", created, got)
+	}
+}
+
+func TestSlogRecordTimeReachesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appLogger := InitLogger(config.LoggerConfig{Level: "info", Provider: "file", Filepath: path})
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := slog.NewRecord(created, slog.LevelInfo, "queued", 0)
+	if err := appLogger.Slog().Handler().Handle(context.Background(), record); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := appLogger.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if content := readFile(t, path); !strings.Contains(content, `"time":"2020-01-02T03:04:05Z"`) {
+		t.Errorf("Expected the record time in the file, got %s", content)
+	}
+}
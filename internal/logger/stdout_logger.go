@@ -12,18 +12,13 @@ import (
 type StdoutLogger struct {
 	config config.LoggerConfig
 	logger zerolog.Logger
-	fields map[string]interface{}
 }
 
 func NewStdoutLogger(cfg config.LoggerConfig) *StdoutLogger {
 	var logger zerolog.Logger
 
-	// Set log level
-	level := parseLogLevel(cfg.Level)
-	zerolog.SetGlobalLevel(level)
-
 	if cfg.Format == "json" {
-		logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+		logger = zerolog.New(os.Stdout)
 	} else {
 		// Console format with colors
 		output := zerolog.ConsoleWriter{
@@ -31,30 +26,21 @@ func NewStdoutLogger(cfg config.LoggerConfig) *StdoutLogger {
 			TimeFormat: time.RFC3339,
 			NoColor:    false,
 		}
-		logger = zerolog.New(output).With().Timestamp().Logger()
+		logger = zerolog.New(output)
 	}
 
 	return &StdoutLogger{
 		config: cfg,
 		logger: logger,
-		fields: make(map[string]interface{}),
 	}
 }
 
-func (l *StdoutLogger) AddField(key string, value interface{}) {
-	l.fields[key] = value
+// With returns a child logger that adds fields to every log
+func (l *StdoutLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
 }
 
 func (l *StdoutLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
-	// Merge stored fields with provided fields
-	mergedFields := make(map[string]interface{})
-	for k, v := range l.fields {
-		mergedFields[k] = v
-	}
-	for k, v := range fields {
-		mergedFields[k] = v
-	}
-
 	// Create log event based on level
 	var event *zerolog.Event
 	switch level {
@@ -72,8 +58,10 @@ func (l *StdoutLogger) Log(ctx context.Context, level, message string, fields ma
 		event = l.logger.Info()
 	}
 
+	event = event.Time(zerolog.TimestampFieldName, logTime(ctx))
+
 	// Add fields to event
-	for key, value := range mergedFields {
+	for key, value := range fields {
 		event = event.Interface(key, value)
 	}
 
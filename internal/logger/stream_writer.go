@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+const (
+	streamDialTimeout  = 5 * time.Second
+	streamWriteTimeout = 5 * time.Second
+	streamMinBackoff   = 100 * time.Millisecond
+)
+
+// streamWriter queues encoded logs and writes them on its own goroutine,
+// which owns the connection, reconnects with backoff when it drops and keeps
+// up to QueueSize logs while disconnected. The Logstash and syslog providers
+// share it and only supply how to dial and how to frame a log.
+type streamWriter struct {
+	// name identifies the provider in diagnostics
+	name    string
+	address string
+	// dial and frame run on the writer goroutine
+	dial  func(dialer *net.Dialer) (net.Conn, error)
+	frame func(msg []byte) []byte
+
+	maxBackoff time.Duration
+
+	queue   chan []byte
+	stop    chan struct{}
+	abort   chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	closed  bool
+	mu      sync.RWMutex
+	dropped atomic.Uint64
+
+	// connection is only touched by the writer goroutine
+	connection net.Conn
+}
+
+func newStreamWriter(name, address string, cfg config.LoggerConfig, dial func(*net.Dialer) (net.Conn, error), frame func([]byte) []byte) *streamWriter {
+	w := &streamWriter{
+		name:       name,
+		address:    address,
+		dial:       dial,
+		frame:      frame,
+		maxBackoff: cfg.ReconnectMaxBackoff,
+		stop:       make(chan struct{}),
+		abort:      make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	if w.maxBackoff <= 0 {
+		w.maxBackoff = 30 * time.Second
+	}
+
+	queueSize := cfg.QueueSize
+	if queueSize <= 0 {
+		queueSize = 10000
+	}
+	w.queue = make(chan []byte, queueSize)
+
+	go w.run()
+
+	return w
+}
+
+// enqueue hands a log to the writer without blocking, dropping it when the
+// queue is full
+func (w *streamWriter) enqueue(msg []byte) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		w.dropped.Add(1)
+		return
+	}
+
+	select {
+	case w.queue <- msg:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// Dropped reports how many logs were dropped because the queue was full
+func (w *streamWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close stops accepting logs and waits until the queued ones are sent or ctx
+// is done
+func (w *streamWriter) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.stop)
+	}
+	w.mu.Unlock()
+
+	var err error
+	select {
+	case <-w.done:
+	case <-ctx.Done():
+		w.once.Do(func() { close(w.abort) })
+		<-w.done
+		err = fmt.Errorf("%s logger closed with %d logs unsent: %w", w.name, len(w.queue), ctx.Err())
+	}
+
+	if dropped := w.dropped.Load(); dropped > 0 {
+		fmt.Printf("%s logger dropped %d logs\n", w.name, dropped)
+	}
+	return err
+}
+
+func (w *streamWriter) run() {
+	defer close(w.done)
+	defer w.disconnect()
+
+	for {
+		select {
+		case msg := <-w.queue:
+			if !w.send(msg) {
+				return
+			}
+		case <-w.stop:
+			// Send what is left, then stop
+			for {
+				select {
+				case msg := <-w.queue:
+					if !w.send(msg) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes msg, reconnecting with backoff until it succeeds. It returns
+// false when the writer is aborted first.
+func (w *streamWriter) send(msg []byte) bool {
+	for backoff := streamMinBackoff; ; backoff = min(backoff*2, w.maxBackoff) {
+		err := w.write(msg)
+		if err == nil {
+			return true
+		}
+		fmt.Printf("Failed to send log to %s at %s: %v\n", w.name, w.address, err)
+
+		if !w.wait(backoff) {
+			return false
+		}
+	}
+}
+
+func (w *streamWriter) write(msg []byte) error {
+	if w.connection == nil {
+		conn, err := w.dial(&net.Dialer{Timeout: streamDialTimeout})
+		if err != nil {
+			return err
+		}
+		w.connection = conn
+	}
+
+	w.connection.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if _, err := w.connection.Write(w.frame(msg)); err != nil {
+		w.disconnect()
+		return err
+	}
+	return nil
+}
+
+func (w *streamWriter) disconnect() {
+	if w.connection != nil {
+		w.connection.Close()
+		w.connection = nil
+	}
+}
+
+// wait sleeps for the backoff and reports false if the writer is aborted
+// meanwhile
+func (w *streamWriter) wait(backoff time.Duration) bool {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-w.abort:
+		return false
+	}
+}
@@ -0,0 +1,257 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+const (
+	syslogDefaultSocket = "/dev/log"
+
+	// syslogSDID names the structured data element carrying the fields. 32473
+	// is the private enterprise number reserved for documentation (RFC 5612).
+	syslogSDID = "fields@32473"
+)
+
+// syslogFacilities maps facility names to their RFC 5424 codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogLogger sends RFC 5424 messages to a syslog daemon over UDP, TCP with
+// octet-counting framing or a unix socket such as /dev/log. Fields travel as
+// structured data. Log only queues the message for the stream writer.
+type SyslogLogger struct {
+	*streamWriter
+
+	config config.LoggerConfig
+
+	// network is only changed by the writer goroutine once constructed
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+	procID   string
+}
+
+func NewSyslogLogger(cfg config.LoggerConfig) *SyslogLogger {
+	facility := syslogFacilities["local0"]
+	if cfg.Facility != "" {
+		code, ok := syslogFacilities[strings.ToLower(cfg.Facility)]
+		if !ok {
+			fmt.Printf("Unknown syslog facility: %s\n", cfg.Facility)
+			return nil
+		}
+		facility = code
+	}
+
+	logger := &SyslogLogger{
+		config:   cfg,
+		address:  cfg.Url,
+		facility: facility,
+		appName:  syslogHeaderField(cfg.AppName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+
+	switch cfg.Transport {
+	case "udp", "tcp":
+		logger.network = cfg.Transport
+		if logger.address == "" {
+			fmt.Printf("Syslog address not configured\n")
+			return nil
+		}
+	case "", "unix":
+		logger.network = "unixgram"
+		if logger.address == "" {
+			logger.address = syslogDefaultSocket
+		}
+	default:
+		fmt.Printf("Unknown syslog transport: %s\n", cfg.Transport)
+		return nil
+	}
+
+	if logger.appName == "-" {
+		logger.appName = syslogHeaderField(filepath.Base(os.Args[0]), 48)
+	}
+	hostname, _ := os.Hostname()
+	logger.hostname = syslogHeaderField(hostname, 255)
+
+	logger.streamWriter = newStreamWriter("syslog", logger.address, cfg, logger.dial, logger.frame)
+
+	return logger
+}
+
+// With returns a child logger that adds fields to every log
+func (l *SyslogLogger) With(fields map[string]interface{}) AppLogger {
+	return newChildLogger(l, fields)
+}
+
+func (l *SyslogLogger) Log(ctx context.Context, level, message string, fields map[string]interface{}) {
+	l.enqueue(l.format(logTime(ctx), level, message, fields))
+}
+
+// format renders an RFC 5424 message:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [fields@32473 key="value"] MSG
+func (l *SyslogLogger) format(now time.Time, level, message string, fields map[string]interface{}) []byte {
+	var b strings.Builder
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(l.facility*8 + syslogSeverity(level)))
+	b.WriteString(">1 ")
+	b.WriteString(now.Format("2006-01-02T15:04:05.000000Z07:00"))
+	b.WriteByte(' ')
+	b.WriteString(l.hostname)
+	b.WriteByte(' ')
+	b.WriteString(l.appName)
+	b.WriteByte(' ')
+	b.WriteString(l.procID)
+	b.WriteString(" - ")
+	writeStructuredData(&b, fields)
+	if message != "" {
+		b.WriteByte(' ')
+		b.WriteString(message)
+	}
+	return []byte(b.String())
+}
+
+func writeStructuredData(b *strings.Builder, fields map[string]interface{}) {
+	if len(fields) == 0 {
+		b.WriteByte('-')
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b.WriteByte('[')
+	b.WriteString(syslogSDID)
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(syslogParamName(k))
+		b.WriteString(`="`)
+		b.WriteString(syslogParamValue(fields[k]))
+		b.WriteByte('"')
+	}
+	b.WriteByte(']')
+}
+
+// syslogSeverity maps a level to its RFC 5424 severity
+func syslogSeverity(level string) int {
+	switch level {
+	case "panic":
+		return 0 // emergency
+	case "fatal":
+		return 2 // critical
+	case "error":
+		return 3
+	case "warn":
+		return 4
+	case "info":
+		return 6
+	}
+	return 7 // debug
+}
+
+// syslogHeaderField keeps the printable ASCII of a header field, truncated
+// to max, and returns the nil value "-" when nothing is left
+func syslogHeaderField(s string, max int) string {
+	var b strings.Builder
+	for i := 0; i < len(s) && b.Len() < max; i++ {
+		if s[i] > ' ' && s[i] < 127 {
+			b.WriteByte(s[i])
+		}
+	}
+	if b.Len() == 0 {
+		return "-"
+	}
+	return b.String()
+}
+
+// syslogParamName replaces the characters structured data names cannot
+// hold and truncates the name to 32 characters
+func syslogParamName(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if c <= ' ' || c >= 127 || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
+
+// syslogParamValue renders a field value, escaping '"', '\' and ']'. Maps
+// and slices are rendered as JSON.
+func syslogParamValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case map[string]interface{}, []interface{}, []string:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			s = fmt.Sprint(v)
+		} else {
+			s = string(encoded)
+		}
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if r == '"' || r == '\\' || r == ']' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (l *SyslogLogger) dial(dialer *net.Dialer) (net.Conn, error) {
+	conn, err := dialer.Dial(l.network, l.address)
+	if err != nil && l.network == "unixgram" {
+		// Some daemons listen on a stream socket instead
+		if conn, err = dialer.Dial("unix", l.address); err == nil {
+			l.network = "unix"
+		}
+	}
+	return conn, err
+}
+
+// frame prepares one message for the transport. TCP frames it with its
+// length (RFC 6587 octet counting); datagram transports send one message per
+// packet and a unix stream socket gets newline terminated messages.
+func (l *SyslogLogger) frame(msg []byte) []byte {
+	switch l.network {
+	case "tcp":
+		return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	case "unix":
+		return append(msg, '\n')
+	}
+	return msg
+}
@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/your-org/boilerplate-go/internal/config"
+)
+
+// readOctetCounted reads one RFC 6587 octet-counting frame
+func readOctetCounted(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(length, " "))
+	if err != nil {
+		return "", err
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return "", err
+	}
+	return string(msg), nil
+}
+
+func TestSyslogFormat(t *testing.T) {
+	l := &SyslogLogger{facility: syslogFacilities["local3"], hostname: "web-1", appName: "api", procID: "42"}
+	now := time.Date(2026, 3, 1, 12, 30, 45, 123456000, time.UTC)
+
+	got := string(l.format(now, "warn", "slow query", map[string]interface{}{
+		"user_id": 7,
+		"query":   `select "x" from [t]`,
+		"path":    `C:\tmp`,
+		"bad key": true,
+	}))
+	want := `<156>1 2026-03-01T12:30:45.123456Z web-1 api 42 - [fields@32473 bad_key="true" path="C:\\tmp" query="select \"x\" from [t\]" user_id="7"] slow query`
+	if got != want {
+		t.Errorf("Unexpected message\n got: %s\nwant: %s", got, want)
+	}
+
+	if got := string(l.format(now, "info", "ready", nil)); got != `<158>1 2026-03-01T12:30:45.123456Z web-1 api 42 - - ready` {
+		t.Errorf("Expected the nil structured data without fields, got %s", got)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	cases := map[string]int{"panic": 0, "fatal": 2, "error": 3, "warn": 4, "info": 6, "debug": 7, "trace": 7}
+	for level, want := range cases {
+		if got := syslogSeverity(level); got != want {
+			t.Errorf("Expected %s to map to severity %d, got %d", level, want, got)
+		}
+	}
+}
+
+func TestSyslogParamName(t *testing.T) {
+	if got := syslogParamName(`a=b]"c d`); got != "a_b__c_d" {
+		t.Errorf("Expected the invalid characters replaced, got %s", got)
+	}
+	if got := syslogParamName(strings.Repeat("k", 40)); len(got) != 32 {
+		t.Errorf("Expected the name truncated to 32 characters, got %d", len(got))
+	}
+}
+
+func TestNewSyslogLoggerRejectsInvalidConfig(t *testing.T) {
+	if l := NewSyslogLogger(config.LoggerConfig{Transport: "udp", Url: "127.0.0.1:514", Facility: "nope"}); l != nil {
+		t.Error("Expected an unknown facility to be rejected")
+	}
+	if l := NewSyslogLogger(config.LoggerConfig{Transport: "tls", Url: "127.0.0.1:514"}); l != nil {
+		t.Error("Expected an unknown transport to be rejected")
+	}
+	if l := NewSyslogLogger(config.LoggerConfig{Transport: "tcp"}); l != nil {
+		t.Error("Expected a missing address to be rejected")
+	}
+}
+
+func TestSyslogLoggerUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	l := NewSyslogLogger(config.LoggerConfig{Transport: "udp", Url: conn.LocalAddr().String(), Facility: "daemon", AppName: "api"})
+	l.Log(context.Background(), "error", "boom", map[string]interface{}{"request_id": "abc"})
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := string(buf[:n])
+	if !strings.HasPrefix(msg, "<27>1 ") {
+		t.Errorf("Expected daemon.err priority, got %s", msg)
+	}
+	if !strings.Contains(msg, " api "+strconv.Itoa(os.Getpid())+" - ") || !strings.HasSuffix(msg, `[fields@32473 request_id="abc"] boom`) {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+}
+
+func TestSyslogLoggerTCPOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	messages := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			msg, err := readOctetCounted(r)
+			if err != nil {
+				close(messages)
+				return
+			}
+			messages <- msg
+		}
+	}()
+
+	l := NewSyslogLogger(config.LoggerConfig{Transport: "tcp", Url: listener.Addr().String()})
+	l.Log(context.Background(), "info", "first line\nsecond line", nil)
+	l.Log(context.Background(), "debug", "next", map[string]interface{}{"n": 2})
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	first, second := <-messages, <-messages
+	if !strings.HasPrefix(first, "<134>1 ") || !strings.HasSuffix(first, " - first line\nsecond line") {
+		t.Errorf("Expected the multi-line message in a single frame, got %q", first)
+	}
+	if !strings.HasPrefix(second, "<135>1 ") || !strings.HasSuffix(second, `[fields@32473 n="2"] next`) {
+		t.Errorf("Unexpected second message: %q", second)
+	}
+}
+
+func TestSyslogLoggerUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+
+	l := NewSyslogLogger(config.LoggerConfig{Transport: "unix", Url: path})
+	l.Log(context.Background(), "warn", "local", nil)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg := string(buf[:n]); !strings.HasPrefix(msg, "<132>1 ") || !strings.HasSuffix(msg, " - - local") {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+}
+
+func TestSyslogLoggerCloseGivesUpWhenUnreachable(t *testing.T) {
+	l := NewSyslogLogger(config.LoggerConfig{Transport: "unix", Url: filepath.Join(t.TempDir(), "missing.sock")})
+	l.Log(context.Background(), "info", "lost", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline error, got %v", err)
+	}
+}
+
+func TestConcurrentLogAndWithSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	l := NewSyslogLogger(config.LoggerConfig{Transport: "udp", Url: conn.LocalAddr().String()})
+	hammer(l)
+	if err := l.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestInitLoggerSyslogProvider(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	appLogger := InitLogger(config.LoggerConfig{Level: "info", Provider: "syslog", Transport: "udp", Url: conn.LocalAddr().String()})
+	defer appLogger.Close(context.Background())
+
+	if _, ok := appLogger.AppLogger.(*SyslogLogger); !ok {
+		t.Errorf("Expected a syslog provider, got %T", appLogger.AppLogger)
+	}
+}
+
+func TestSyslogDefaultsFromLoadedConfig(t *testing.T) {
+	t.Setenv("APP_LOGGER_PROVIDER", "syslog")
+	t.Cleanup(viper.Reset)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	l := NewSyslogLogger(cfg.Logger)
+	if l == nil {
+		t.Fatal("Expected a syslog logger")
+	}
+	defer l.Close(context.Background())
+
+	if l.network != "unixgram" || l.address != syslogDefaultSocket {
+		t.Errorf("Expected the local socket, got %s %s", l.network, l.address)
+	}
+}
+
+func TestSyslogSinkIgnoresOtherProviderURL(t *testing.T) {
+	parent := config.LoggerConfig{Provider: "elasticsearch", Url: "http://localhost:9200", Transport: "tcp", Username: "elastic"}
+
+	sinkCfg := inheritSinkConfig(parent, config.LoggerConfig{Provider: "syslog"})
+	if sinkCfg.Url != "" || sinkCfg.Transport != "" || sinkCfg.Username != "" {
+		t.Errorf("Expected no connection options from another provider, got %+v", sinkCfg)
+	}
+
+	sinkCfg = inheritSinkConfig(config.LoggerConfig{Provider: "syslog", Url: "logs:514", Transport: "udp"}, config.LoggerConfig{})
+	if sinkCfg.Url != "logs:514" || sinkCfg.Transport != "udp" {
+		t.Errorf("Expected the connection options of the same provider, got %+v", sinkCfg)
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-org/boilerplate-go/internal/response"
+)
+
+// AdminAuth protects admin endpoints with a static token sent as a bearer
+// token or in the X-Api-Key header. Without a configured token every
+// request is rejected.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader("X-Api-Key")
+		if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			response.Error(c, http.StatusUnauthorized, "unauthorized", "A valid admin token is required")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newAdminRouter(token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", AdminAuth(token), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestAdminAuth(t *testing.T) {
+	cases := []struct {
+		name    string
+		token   string
+		headers map[string]string
+		want    int
+	}{
+		{"missing token", "secret", nil, http.StatusUnauthorized},
+		{"wrong bearer token", "secret", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"wrong api key", "secret", map[string]string{"X-Api-Key": "nope"}, http.StatusUnauthorized},
+		{"non bearer authorization", "secret", map[string]string{"Authorization": "Basic secret"}, http.StatusUnauthorized},
+		{"valid bearer token", "secret", map[string]string{"Authorization": "Bearer secret"}, http.StatusNoContent},
+		{"valid api key", "secret", map[string]string{"X-Api-Key": "secret"}, http.StatusNoContent},
+		{"bearer token wins over api key", "secret", map[string]string{"Authorization": "Bearer nope", "X-Api-Key": "secret"}, http.StatusUnauthorized},
+		{"no configured token", "", map[string]string{"Authorization": "Bearer "}, http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			newAdminRouter(tc.token).ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Errorf("Expected status %d, got %d: %s", tc.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
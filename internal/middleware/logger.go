@@ -1,16 +1,19 @@
 package middleware
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"github.com/your-org/boilerplate-go/internal/logger"
+	"github.com/your-org/boilerplate-go/pkg/events"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // Logger middleware logs HTTP requests with OpenTelemetry integration
-func Logger(logger zerolog.Logger) gin.HandlerFunc {
+func Logger(log zerolog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
@@ -23,6 +26,15 @@ func Logger(logger zerolog.Logger) gin.HandlerFunc {
 			c.Header("X-Request-ID", requestID)
 		}
 
+		// Carry the request fields in the context so every log of the request
+		// includes them, and correlate the events it publishes by request ID
+		ctx := logger.WithContext(c.Request.Context(), map[string]interface{}{
+			logger.RequestIDField: requestID,
+			logger.RouteField:     c.FullPath(),
+			logger.ClientIPField:  c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(events.WithCorrelationID(ctx, requestID))
+
 		// Process request
 		c.Next()
 
@@ -33,7 +45,7 @@ func Logger(logger zerolog.Logger) gin.HandlerFunc {
 		spanCtx := trace.SpanContextFromContext(c.Request.Context())
 
 		// Build log event
-		logEvent := logger.Info().
+		logEvent := log.Info().
 			Str("method", c.Request.Method).
 			Str("path", path).
 			Int("status", c.Writer.Status()).
@@ -43,6 +55,13 @@ func Logger(logger zerolog.Logger) gin.HandlerFunc {
 			Str("request_id", requestID).
 			Int("body_size", c.Writer.Size())
 
+		if route := c.FullPath(); route != "" {
+			logEvent.Str("route", route)
+		}
+		if userID, ok := logger.FromContext(c.Request.Context())[logger.UserIDField]; ok {
+			logEvent.Interface("user_id", userID)
+		}
+
 		// Add trace information if available
 		if spanCtx.IsValid() {
 			logEvent.Str("trace_id", spanCtx.TraceID().String()).
@@ -65,6 +84,16 @@ func Logger(logger zerolog.Logger) gin.HandlerFunc {
 	}
 }
 
+// SetUserID adds the authenticated user to the fields logged with the request
+// and makes it the actor of the events the request publishes. Authentication
+// middlewares call it once they know who the user is.
+func SetUserID(c *gin.Context, userID interface{}) {
+	ctx := logger.WithContext(c.Request.Context(), map[string]interface{}{
+		logger.UserIDField: userID,
+	})
+	c.Request = c.Request.WithContext(events.WithActorID(ctx, fmt.Sprint(userID)))
+}
+
 // Recovery middleware recovers from panics with enhanced logging
 func Recovery(logger zerolog.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/your-org/boilerplate-go/internal/logger"
+	"github.com/your-org/boilerplate-go/internal/user/application"
+	"github.com/your-org/boilerplate-go/internal/user/domain"
+	"github.com/your-org/boilerplate-go/internal/user/presentation"
+	"github.com/your-org/boilerplate-go/pkg/events"
+)
+
+// decodeLines decodes every JSON log line of buf
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("Invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func TestLoggerAddsRequestFieldsToHandlerLogs(t *testing.T) {
+	var handlerOutput, requestOutput bytes.Buffer
+	appLogger := &logger.Logger{Logger: zerolog.New(&handlerOutput)}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Logger(zerolog.New(&requestOutput)))
+	router.GET("/users/:id", func(c *gin.Context) {
+		SetUserID(c, "user-42")
+		appLogger.LogInfo(c.Request.Context(), "Loading user")
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	req.RemoteAddr = "203.0.113.9:4321"
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	want := map[string]interface{}{
+		"request_id": "req-1",
+		"route":      "/users/:id",
+		"client_ip":  "203.0.113.9",
+		"user_id":    "user-42",
+	}
+
+	handlerLines := decodeLines(t, &handlerOutput)
+	if len(handlerLines) != 1 || handlerLines[0]["message"] != "Loading user" {
+		t.Fatalf("Expected the handler log, got %v", handlerLines)
+	}
+	for key, value := range want {
+		if handlerLines[0][key] != value {
+			t.Errorf("Expected %s=%v in the handler log, got %v", key, value, handlerLines[0][key])
+		}
+	}
+
+	requestLines := decodeLines(t, &requestOutput)
+	if len(requestLines) != 1 || requestLines[0]["message"] != "HTTP Request" {
+		t.Fatalf("Expected the request log, got %v", requestLines)
+	}
+	for key, value := range want {
+		if requestLines[0][key] != value {
+			t.Errorf("Expected %s=%v in the request log, got %v", key, value, requestLines[0][key])
+		}
+	}
+}
+
+func TestLoggerGeneratesRequestID(t *testing.T) {
+	var output bytes.Buffer
+	appLogger := &logger.Logger{Logger: zerolog.New(&output)}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Logger(zerolog.Nop()))
+	router.GET("/health", func(c *gin.Context) {
+		appLogger.LogInfo(c.Request.Context(), "Checking health")
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	requestID := rec.Header().Get("X-Request-ID")
+	if requestID == "" {
+		t.Fatal("Expected a generated X-Request-ID header")
+	}
+	lines := decodeLines(t, &output)
+	if lines[0]["request_id"] != requestID {
+		t.Errorf("Expected the generated request ID %s in the log, got %v", requestID, lines[0]["request_id"])
+	}
+	if _, ok := lines[0]["user_id"]; ok {
+		t.Errorf("Expected no user_id without SetUserID, got %v", lines[0]["user_id"])
+	}
+}
+
+// memoryUserRepository stores users in a map for the HTTP path tests
+type memoryUserRepository struct {
+	users map[uint]*domain.User
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
+	return r.users[id], nil
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id uint) error {
+	delete(r.users, id)
+	return nil
+}
+
+func (r *memoryUserRepository) List(ctx context.Context, limit, offset int) ([]*domain.User, error) {
+	return nil, nil
+}
+
+func (r *memoryUserRepository) Count(ctx context.Context) (int64, error) {
+	return int64(len(r.users)), nil
+}
+
+func TestLoggerAddsEventMetadataThroughUserService(t *testing.T) {
+	bus := events.NewEventBus(nil)
+	defer bus.Close()
+
+	published := make(chan *domain.UserCreatedEvent, 1)
+	if _, err := bus.Subscribe(domain.UserCreatedTopic, func(event *domain.UserCreatedEvent) {
+		published <- event
+	}); err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	appLogger := &logger.Logger{Logger: zerolog.Nop()}
+	service := application.NewUserService(&memoryUserRepository{users: make(map[uint]*domain.User)}, bus, appLogger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Logger(zerolog.Nop()))
+	router.Use(func(c *gin.Context) {
+		SetUserID(c, 42)
+		c.Next()
+	})
+	presentation.NewUserController(service, zerolog.Nop()).RegisterRoutes(router.Group(""))
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"John Doe","email":"john@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	select {
+	case event := <-published:
+		if event.ActorID != "42" || event.CorrelationID != "req-1" {
+			t.Errorf("Expected actor 42 and correlation req-1, got %+v", event.Metadata)
+		}
+	default:
+		t.Fatal("Expected a user.created event")
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/your-org/boilerplate-go/internal/config"
 	"github.com/your-org/boilerplate-go/internal/logger"
 	"github.com/your-org/boilerplate-go/internal/middleware"
+	"github.com/your-org/boilerplate-go/internal/response"
 	"github.com/your-org/boilerplate-go/internal/user/presentation"
+	"github.com/your-org/boilerplate-go/pkg/events"
 	"gorm.io/gorm"
 )
 
@@ -22,11 +24,12 @@ type Server struct {
 	db             *gorm.DB
 	logger         *logger.Logger
 	router         *gin.Engine
+	eventBus       events.EventBus
 	userController *presentation.UserController
 }
 
 // New creates a new server instance
-func New(cfg *config.Config, db *gorm.DB, appLogger *logger.Logger, userController *presentation.UserController) *Server {
+func New(cfg *config.Config, db *gorm.DB, appLogger *logger.Logger, eventBus events.EventBus, userController *presentation.UserController) *Server {
 	// Set Gin mode
 	gin.SetMode(cfg.Server.Mode)
 
@@ -38,6 +41,7 @@ func New(cfg *config.Config, db *gorm.DB, appLogger *logger.Logger, userControll
 		db:             db,
 		logger:         appLogger,
 		router:         router,
+		eventBus:       eventBus,
 		userController: userController,
 	}
 }
@@ -115,6 +119,16 @@ func (s *Server) setupRoutes() {
 		// User routes - using injected controller
 		s.userController.RegisterRoutes(v1)
 	}
+
+	// Admin routes
+	admin := s.router.Group("/admin", middleware.AdminAuth(s.config.Server.AdminToken))
+	{
+		admin.GET("/events", s.eventBusInfo)
+		admin.GET("/log-level", s.logLevel)
+		admin.PUT("/log-level", s.setLogLevel)
+		admin.DELETE("/log-level", s.resetLogLevel)
+		admin.GET("/log-config", s.logConfig)
+	}
 }
 
 // healthCheck handles health check requests
@@ -132,3 +146,63 @@ func (s *Server) welcome(c *gin.Context) {
 		"version": "1.0.0",
 	})
 }
+
+// eventBusInfo returns the topics, subscribers and counters of the event bus
+func (s *Server) eventBusInfo(c *gin.Context) {
+	response.Success(c, s.eventBus.Inspect())
+}
+
+// logLevelRequest changes the log level. With a TTL such as "15m" the change
+// is temporary; without one it lasts until the next change.
+type logLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+	TTL   string `json:"ttl"`
+}
+
+// logLevel returns the effective log level and when an override expires
+func (s *Server) logLevel(c *gin.Context) {
+	response.Success(c, s.logger.Levels.State())
+}
+
+// setLogLevel changes the log level of every provider at runtime
+func (s *Server) setLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		parsed, err := time.ParseDuration(req.TTL)
+		if err != nil || parsed <= 0 {
+			response.BadRequest(c, "invalid ttl", "ttl must be a positive duration such as 15m")
+			return
+		}
+		ttl = parsed
+	}
+
+	if err := s.logger.Levels.Set(req.Level, ttl); err != nil {
+		response.BadRequest(c, err.Error())
+		return
+	}
+
+	state := s.logger.Levels.State()
+	s.logger.LogWarn(c.Request.Context(), "Log level changed", map[string]interface{}{
+		"level":     state.Level,
+		"ttl":       req.TTL,
+		"client_ip": c.ClientIP(),
+	})
+	response.Success(c, state)
+}
+
+// logConfig returns the effective configuration of the log providers
+func (s *Server) logConfig(c *gin.Context) {
+	response.Success(c, s.logger.Describe())
+}
+
+// resetLogLevel drops a temporary override
+func (s *Server) resetLogLevel(c *gin.Context) {
+	s.logger.Levels.Reset()
+	response.Success(c, s.logger.Levels.State())
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/your-org/boilerplate-go/internal/config"
+	"github.com/your-org/boilerplate-go/internal/logger"
+	"github.com/your-org/boilerplate-go/pkg/events"
+)
+
+const testAdminToken = "secret"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Server.Mode = "test"
+	cfg.Server.AdminToken = testAdminToken
+
+	appLogger := logger.InitLogger(config.LoggerConfig{Level: "info", Format: "json", Provider: "stdout"})
+	t.Cleanup(func() { appLogger.Close(context.Background()) })
+
+	bus := events.NewEventBus(nil)
+	t.Cleanup(func() { bus.Close() })
+
+	s := New(cfg, nil, &appLogger, bus, nil)
+	s.setupRoutes()
+	return s
+}
+
+// serve sends a request with the admin token unless token is empty
+func serve(s *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeData decodes the data of a success response into v
+func decodeData(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid response %s: %v", rec.Body.String(), err)
+	}
+	if err := json.Unmarshal(body.Data, v); err != nil {
+		t.Fatalf("Invalid data %s: %v", body.Data, err)
+	}
+}
+
+func TestAdminEventsRequiresToken(t *testing.T) {
+	s := newTestServer(t)
+
+	if rec := serve(s, http.MethodGet, "/admin/events", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", rec.Code)
+	}
+	if rec := serve(s, http.MethodGet, "/admin/events", "wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with a wrong token, got %d", rec.Code)
+	}
+}
+
+func TestAdminEvents(t *testing.T) {
+	s := newTestServer(t)
+	if _, err := s.eventBus.Subscribe("user.created", func(events.Event) {}); err != nil {
+		t.Fatal(err)
+	}
+	s.eventBus.Publish("user.created", events.NewBaseEvent("user.created"))
+
+	rec := serve(s, http.MethodGet, "/admin/events", testAdminToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var info events.BusInfo
+	decodeData(t, rec, &info)
+	if len(info.Topics) != 1 || info.Topics[0].Topic != "user.created" || len(info.Topics[0].Subscribers) != 1 {
+		t.Errorf("Unexpected topics: %+v", info.Topics)
+	}
+	if info.Metrics.Published != 1 {
+		t.Errorf("Expected 1 published event, got %+v", info.Metrics)
+	}
+}
+
+func TestAdminLogRoutesRequireToken(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/admin/log-level"},
+		{http.MethodPut, "/admin/log-level"},
+		{http.MethodDelete, "/admin/log-level"},
+		{http.MethodGet, "/admin/log-config"},
+	} {
+		if rec := serve(s, route.method, route.path, "wrong", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for %s %s, got %d", route.method, route.path, rec.Code)
+		}
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := serve(s, http.MethodGet, "/admin/log-level", testAdminToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var state logger.LevelState
+	decodeData(t, rec, &state)
+	if state.Level != "info" || state.Base != "info" || state.Override {
+		t.Errorf("Expected the configured level, got %+v", state)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := serve(s, http.MethodPut, "/admin/log-level", testAdminToken, `{"level":"debug"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var state logger.LevelState
+	decodeData(t, rec, &state)
+	if state.Level != "debug" || state.Base != "debug" || state.Override {
+		t.Errorf("Expected a new base level, got %+v", state)
+	}
+	if got := s.logger.Levels.State(); got.Level != "debug" {
+		t.Errorf("Expected the logger level to change, got %+v", got)
+	}
+}
+
+func TestSetLogLevelRejectsInvalidInput(t *testing.T) {
+	s := newTestServer(t)
+
+	for name, body := range map[string]string{
+		"unknown level": `{"level":"verbose"}`,
+		"missing level": `{"ttl":"15m"}`,
+		"invalid ttl":   `{"level":"debug","ttl":"soon"}`,
+		"negative ttl":  `{"level":"debug","ttl":"-1m"}`,
+		"invalid json":  `{"level":`,
+	} {
+		if rec := serve(s, http.MethodPut, "/admin/log-level", testAdminToken, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d: %s", name, rec.Code, rec.Body.String())
+		}
+	}
+
+	if got := s.logger.Levels.State(); got.Level != "info" || got.Override {
+		t.Errorf("Expected the level to stay unchanged, got %+v", got)
+	}
+}
+
+func TestResetLogLevel(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := serve(s, http.MethodPut, "/admin/log-level", testAdminToken, `{"level":"error","ttl":"15m"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var state logger.LevelState
+	decodeData(t, rec, &state)
+	if state.Level != "error" || state.Base != "info" || !state.Override || state.ExpiresAt == nil {
+		t.Errorf("Expected a temporary override, got %+v", state)
+	}
+
+	rec = serve(s, http.MethodDelete, "/admin/log-level", testAdminToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	state = logger.LevelState{}
+	decodeData(t, rec, &state)
+	if state.Level != "info" || state.Override || state.ExpiresAt != nil {
+		t.Errorf("Expected the base level after reset, got %+v", state)
+	}
+}
+
+func TestLogConfig(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := serve(s, http.MethodGet, "/admin/log-config", testAdminToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var info logger.LoggerInfo
+	decodeData(t, rec, &info)
+	if info.Level.Level != "info" {
+		t.Errorf("Expected the effective level, got %+v", info.Level)
+	}
+	if len(info.Providers) != 1 || info.Providers[0].Provider != "stdout" || info.Providers[0].Format != "json" {
+		t.Errorf("Unexpected providers: %+v", info.Providers)
+	}
+}
@@ -21,24 +21,9 @@ import (
 
 func InitTelemetry(ctx context.Context, appConfig *config.Config) func() {
 
-	os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", appConfig.Apm.Url)
-	os.Setenv("OTEL_RESOURCE_ATTRIBUTES", appConfig.Apm.Attributes)
-	os.Setenv("OTEL_EXPORTER_OTLP_HEADERS", appConfig.Apm.Headers)
+	ConfigureExporter(appConfig)
 
-	res, err := resource.New(
-		ctx,
-		resource.WithProcess(),
-		resource.WithOS(),
-		resource.WithTelemetrySDK(),
-		resource.WithContainer(),
-		resource.WithHost(),
-		resource.WithSchemaURL(semconv.SchemaURL),
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(appConfig.Application.Name),
-			semconv.ServiceVersionKey.String("1.0"),
-			semconv.DeploymentEnvironmentKey.String(appConfig.Application.Environment),
-		),
-	)
+	res, err := NewResource(ctx, appConfig)
 	if err != nil {
 		log.Fatalf("OpenTelemetry resource failed: %v", err)
 		return func() {}
@@ -85,6 +70,39 @@ func InitTelemetry(ctx context.Context, appConfig *config.Config) func() {
 	return func() {}
 }
 
+// ConfigureExporter points the OTLP exporters of every signal at the APM
+// endpoint. Values left empty keep whatever the environment already sets.
+func ConfigureExporter(appConfig *config.Config) {
+	setenv("OTEL_EXPORTER_OTLP_ENDPOINT", appConfig.Apm.Url)
+	setenv("OTEL_RESOURCE_ATTRIBUTES", appConfig.Apm.Attributes)
+	setenv("OTEL_EXPORTER_OTLP_HEADERS", appConfig.Apm.Headers)
+}
+
+func setenv(key, value string) {
+	if value != "" {
+		os.Setenv(key, value)
+	}
+}
+
+// NewResource describes the service to every signal: traces, metrics and
+// logs share it so a collector can correlate them
+func NewResource(ctx context.Context, appConfig *config.Config) (*resource.Resource, error) {
+	return resource.New(
+		ctx,
+		resource.WithProcess(),
+		resource.WithOS(),
+		resource.WithTelemetrySDK(),
+		resource.WithContainer(),
+		resource.WithHost(),
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(appConfig.Application.Name),
+			semconv.ServiceVersionKey.String("1.0"),
+			semconv.DeploymentEnvironmentKey.String(appConfig.Application.Environment),
+		),
+	)
+}
+
 func GetTracer() traceOtel.Tracer {
 	return otel.Tracer("banking-router")
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/your-org/boilerplate-go/internal/logger"
 	"github.com/your-org/boilerplate-go/internal/user/domain"
+	"github.com/your-org/boilerplate-go/pkg/events"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -15,13 +16,15 @@ import (
 // UserService handles user business logic
 type UserService struct {
 	userRepo domain.UserRepository
+	eventBus events.EventBus
 	logger   *logger.Logger
 }
 
 // NewUserService creates a new UserService
-func NewUserService(userRepo domain.UserRepository, logger *logger.Logger) *UserService {
+func NewUserService(userRepo domain.UserRepository, eventBus events.EventBus, logger *logger.Logger) *UserService {
 	return &UserService{
 		userRepo: userRepo,
+		eventBus: eventBus,
 		logger:   logger,
 	}
 }
@@ -91,6 +94,8 @@ func (s *UserService) CreateUser(ctx context.Context, name, email string) (*doma
 		"duration": duration.Milliseconds(),
 	})
 
+	s.publish(ctx, domain.NewUserCreatedEvent(createdUser, events.MetadataFromContext(ctx)))
+
 	return createdUser, nil
 }
 
@@ -215,6 +220,17 @@ func (s *UserService) UpdateUser(ctx context.Context, id uint, name, email strin
 		"new_email": user.Email,
 	})
 
+	changes := make(map[string]domain.FieldChange)
+	if user.Name != oldName {
+		changes["name"] = domain.FieldChange{Old: oldName, New: user.Name}
+	}
+	if user.Email != oldEmail {
+		changes["email"] = domain.FieldChange{Old: oldEmail, New: user.Email}
+	}
+	if len(changes) > 0 {
+		s.publish(ctx, domain.NewUserUpdatedEvent(user.ID, changes, events.MetadataFromContext(ctx)))
+	}
+
 	return user, nil
 }
 
@@ -248,6 +264,8 @@ func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
 		"user_id": id,
 	})
 
+	s.publish(ctx, domain.NewUserDeletedEvent(id, events.MetadataFromContext(ctx)))
+
 	return nil
 }
 
@@ -290,3 +308,19 @@ func (s *UserService) ListUsers(ctx context.Context, limit, offset int) ([]*doma
 
 	return users, nil
 }
+
+// publish sends a domain event to the event bus. The repository change has
+// already been committed at this point, so a publish failure is logged
+// instead of being returned to the caller.
+func (s *UserService) publish(ctx context.Context, event events.Event) {
+	if s.eventBus == nil {
+		return
+	}
+
+	if err := s.eventBus.Publish(event.GetName(), event); err != nil {
+		s.logger.LogError(ctx, "Failed to publish user event", err, map[string]interface{}{
+			"event_name": event.GetName(),
+			"event_id":   event.GetID(),
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/your-org/boilerplate-go/internal/logger"
 	"github.com/your-org/boilerplate-go/internal/user/application"
 	"github.com/your-org/boilerplate-go/internal/user/domain"
+	"github.com/your-org/boilerplate-go/pkg/events"
 )
 
 // MockUserRepository is a mock implementation of UserRepository
@@ -79,7 +80,7 @@ func createTestLogger() *logger.Logger {
 func TestUserService_CreateUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	testLogger := createTestLogger()
-	service := application.NewUserService(mockRepo, testLogger)
+	service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 	ctx := context.Background()
 
 	t.Run("successful user creation", func(t *testing.T) {
@@ -127,7 +128,7 @@ func TestUserService_CreateUser(t *testing.T) {
 		// Reset mock for this test
 		mockRepo := new(MockUserRepository)
 		testLogger := createTestLogger()
-		service := application.NewUserService(mockRepo, testLogger)
+		service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 
 		mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(existingUser, nil)
 
@@ -143,7 +144,7 @@ func TestUserService_CreateUser(t *testing.T) {
 func TestUserService_GetUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	testLogger := createTestLogger()
-	service := application.NewUserService(mockRepo, testLogger)
+	service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 	ctx := context.Background()
 
 	t.Run("successful user retrieval", func(t *testing.T) {
@@ -174,7 +175,7 @@ func TestUserService_GetUser(t *testing.T) {
 func TestUserService_GetUserByEmail(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	testLogger := createTestLogger()
-	service := application.NewUserService(mockRepo, testLogger)
+	service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 	ctx := context.Background()
 
 	t.Run("successful user retrieval by email", func(t *testing.T) {
@@ -205,7 +206,7 @@ func TestUserService_GetUserByEmail(t *testing.T) {
 func TestUserService_UpdateUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	testLogger := createTestLogger()
-	service := application.NewUserService(mockRepo, testLogger)
+	service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 	ctx := context.Background()
 
 	t.Run("successful user update", func(t *testing.T) {
@@ -232,7 +233,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 func TestUserService_DeleteUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	testLogger := createTestLogger()
-	service := application.NewUserService(mockRepo, testLogger)
+	service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 	ctx := context.Background()
 
 	t.Run("successful user deletion", func(t *testing.T) {
@@ -256,7 +257,7 @@ func TestUserService_ListUsers(t *testing.T) {
 	t.Run("successful user listing", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		testLogger := createTestLogger()
-		service := application.NewUserService(mockRepo, testLogger)
+		service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 		ctx := context.Background()
 
 		users := []*domain.User{
@@ -277,7 +278,7 @@ func TestUserService_ListUsers(t *testing.T) {
 	t.Run("user listing with default pagination", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		testLogger := createTestLogger()
-		service := application.NewUserService(mockRepo, testLogger)
+		service := application.NewUserService(mockRepo, events.NewEventBus(nil), testLogger)
 		ctx := context.Background()
 
 		users := []*domain.User{
@@ -293,3 +294,100 @@ func TestUserService_ListUsers(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestUserService_PublishesEvents(t *testing.T) {
+	t.Run("user.created is published after creation", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		eventBus := events.NewEventBus(nil)
+		service := application.NewUserService(mockRepo, eventBus, createTestLogger())
+		ctx := events.WithActorID(context.Background(), "admin")
+		ctx = events.WithCorrelationID(ctx, "req-123")
+
+		var received []events.Event
+		eventBus.Subscribe(domain.UserCreatedTopic, func(event events.Event) {
+			received = append(received, event)
+		})
+
+		user := &domain.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+		mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(nil, errors.New("user not found"))
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(user, nil)
+
+		_, err := service.CreateUser(ctx, "John Doe", "john@example.com")
+
+		assert.NoError(t, err)
+		if assert.Len(t, received, 1) {
+			event := received[0].(*domain.UserCreatedEvent)
+			assert.Equal(t, uint(1), event.UserID)
+			assert.Equal(t, "john@example.com", event.Email)
+			assert.Equal(t, "admin", event.ActorID)
+			assert.Equal(t, "req-123", event.CorrelationID)
+		}
+	})
+
+	t.Run("no event is published when the repository fails", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		eventBus := events.NewEventBus(nil)
+		service := application.NewUserService(mockRepo, eventBus, createTestLogger())
+		ctx := context.Background()
+
+		published := false
+		eventBus.Subscribe(domain.UserCreatedTopic, func(event events.Event) { published = true })
+		eventBus.Subscribe(domain.UserDeletedTopic, func(event events.Event) { published = true })
+
+		mockRepo.On("GetByEmail", mock.Anything, "john@example.com").Return(nil, errors.New("user not found"))
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+		mockRepo.On("Delete", mock.Anything, uint(1)).Return(errors.New("db down"))
+
+		_, err := service.CreateUser(ctx, "John Doe", "john@example.com")
+		assert.Error(t, err)
+		err = service.DeleteUser(ctx, 1)
+		assert.Error(t, err)
+
+		assert.False(t, published)
+	})
+
+	t.Run("user.updated carries changed fields", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		eventBus := events.NewEventBus(nil)
+		service := application.NewUserService(mockRepo, eventBus, createTestLogger())
+		ctx := context.Background()
+
+		var received *domain.UserUpdatedEvent
+		eventBus.Subscribe(domain.UserUpdatedTopic, func(event events.Event) {
+			received = event.(*domain.UserUpdatedEvent)
+		})
+
+		user := &domain.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+		mockRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := service.UpdateUser(ctx, 1, "", "johnsmith@example.com")
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, received) {
+			assert.Len(t, received.Changes, 1)
+			assert.Equal(t, domain.FieldChange{Old: "john@example.com", New: "johnsmith@example.com"}, received.Changes["email"])
+		}
+	})
+
+	t.Run("user.deleted is published after deletion", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		eventBus := events.NewEventBus(nil)
+		service := application.NewUserService(mockRepo, eventBus, createTestLogger())
+		ctx := context.Background()
+
+		var received *domain.UserDeletedEvent
+		eventBus.Subscribe(domain.UserDeletedTopic, func(event events.Event) {
+			received = event.(*domain.UserDeletedEvent)
+		})
+
+		mockRepo.On("Delete", mock.Anything, uint(7)).Return(nil)
+
+		err := service.DeleteUser(ctx, 7)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, received) {
+			assert.Equal(t, uint(7), received.UserID)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"github.com/your-org/boilerplate-go/pkg/events"
+)
+
+// User lifecycle event topics
+const (
+	UserCreatedTopic = "user.created"
+	UserUpdatedTopic = "user.updated"
+	UserDeletedTopic = "user.deleted"
+)
+
+// FieldChange holds the previous and current value of a changed field
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// UserCreatedEvent is published after a user has been persisted
+type UserCreatedEvent struct {
+	*events.BaseEvent
+	events.Metadata
+	UserID uint   `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// UserUpdatedEvent is published after a user has been updated
+type UserUpdatedEvent struct {
+	*events.BaseEvent
+	events.Metadata
+	UserID  uint                   `json:"user_id"`
+	Changes map[string]FieldChange `json:"changes"`
+}
+
+// UserDeletedEvent is published after a user has been deleted
+type UserDeletedEvent struct {
+	*events.BaseEvent
+	events.Metadata
+	UserID uint `json:"user_id"`
+}
+
+// NewUserCreatedEvent creates a UserCreatedEvent for the given user
+func NewUserCreatedEvent(user *User, metadata events.Metadata) *UserCreatedEvent {
+	return &UserCreatedEvent{
+		BaseEvent: events.NewBaseEvent(UserCreatedTopic),
+		Metadata:  metadata,
+		UserID:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+	}
+}
+
+// NewUserUpdatedEvent creates a UserUpdatedEvent with the changed fields
+func NewUserUpdatedEvent(userID uint, changes map[string]FieldChange, metadata events.Metadata) *UserUpdatedEvent {
+	return &UserUpdatedEvent{
+		BaseEvent: events.NewBaseEvent(UserUpdatedTopic),
+		Metadata:  metadata,
+		UserID:    userID,
+		Changes:   changes,
+	}
+}
+
+// NewUserDeletedEvent creates a UserDeletedEvent for the given user ID
+func NewUserDeletedEvent(userID uint, metadata events.Metadata) *UserDeletedEvent {
+	return &UserDeletedEvent{
+		BaseEvent: events.NewBaseEvent(UserDeletedTopic),
+		Metadata:  metadata,
+		UserID:    userID,
+	}
+}
@@ -0,0 +1,302 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchHandler handles a batch of events delivered together
+type BatchHandler func(ctx context.Context, events []Event) error
+
+// BatchKeyFunc returns the key used to coalesce events in debounce mode
+type BatchKeyFunc func(event Event) string
+
+// BatchOption configures a batch subscription
+type BatchOption func(*batcher)
+
+// WithDebounce coalesces pending events by key, keeping only the latest
+// event per key, and waits until no event arrived for maxWait before flushing
+func WithDebounce(key BatchKeyFunc) BatchOption {
+	return func(b *batcher) {
+		b.key = key
+	}
+}
+
+// WithBatchRetry retries a failed batch up to attempts times in total,
+// waiting backoff between attempts. Once the subscription is stopped a
+// batch that fails is not retried.
+func WithBatchRetry(attempts int, backoff time.Duration) BatchOption {
+	return func(b *batcher) {
+		if attempts > 0 {
+			b.attempts = attempts
+		}
+		b.backoff = backoff
+	}
+}
+
+// WithBatchErrorHandler sets the function called with a batch that still
+// fails after all retries
+func WithBatchErrorHandler(fn func(events []Event, err error)) BatchOption {
+	return func(b *batcher) {
+		b.onError = fn
+	}
+}
+
+// batcher collects events of a topic and hands them to its handler in
+// batches. Batches are handled one at a time in the order they were flushed;
+// publishers block when a full batch is waiting for the previous one.
+type batcher struct {
+	topic    string
+	maxSize  int
+	maxWait  time.Duration
+	handler  BatchHandler
+	key      BatchKeyFunc
+	attempts int
+	backoff  time.Duration
+	onError  func(events []Event, err error)
+	metrics  *busMetrics
+	stats    *subscriptionStats
+	timeout  time.Duration
+	logger   Logger
+
+	pending []Event
+	keys    map[string]int
+	timer   *time.Timer
+	mu      sync.Mutex
+
+	flushes chan []Event
+	sending sync.WaitGroup
+	quit    chan struct{}
+	done    chan struct{}
+	stopped bool
+	// leftover holds the batches flushed while stopping. The run goroutine
+	// handles them once flushes is closed.
+	leftover [][]Event
+	// runner is the id of the run goroutine. The handler and the error
+	// handler run on it and may stop the batcher by unsubscribing or closing
+	// the bus.
+	runner atomic.Uint64
+}
+
+func newBatcher(topic string, maxSize int, maxWait time.Duration, handler BatchHandler, metrics *busMetrics, opts ...BatchOption) *batcher {
+	b := &batcher{
+		topic:    topic,
+		maxSize:  maxSize,
+		maxWait:  maxWait,
+		handler:  handler,
+		attempts: 1,
+		metrics:  metrics,
+		logger:   zerologLogger{},
+		keys:     make(map[string]int),
+		flushes:  make(chan []Event),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	go b.run()
+	return b
+}
+
+// add queues an event and flushes the batch when it is full
+func (b *batcher) add(event Event) {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return
+	}
+
+	if b.key != nil {
+		key := b.key(event)
+		if i, ok := b.keys[key]; ok {
+			b.pending[i] = event
+		} else {
+			b.keys[key] = len(b.pending)
+			b.pending = append(b.pending, event)
+		}
+	} else {
+		b.pending = append(b.pending, event)
+	}
+
+	if len(b.pending) >= b.maxSize {
+		b.flushLocked()
+		return
+	}
+
+	switch {
+	case b.timer == nil:
+		b.timer = time.AfterFunc(b.maxWait, b.flushOnTimer)
+	case b.key != nil:
+		// Debounce restarts the wait on every event
+		b.timer.Reset(b.maxWait)
+	}
+	b.mu.Unlock()
+}
+
+func (b *batcher) flushOnTimer() {
+	b.mu.Lock()
+	if b.stopped || len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	b.flushLocked()
+}
+
+// flushLocked hands the pending batch to the worker and releases b.mu.
+// The send is tracked so that stop never closes the channel under it; a
+// send still blocked when stop begins leaves the batch to the leftovers.
+func (b *batcher) flushLocked() {
+	batch := b.take()
+	b.sending.Add(1)
+	b.mu.Unlock()
+	defer b.sending.Done()
+
+	select {
+	case b.flushes <- batch:
+	case <-b.quit:
+		b.mu.Lock()
+		b.leftover = append(b.leftover, batch)
+		b.mu.Unlock()
+	}
+}
+
+// take removes and returns the pending batch. Callers must hold b.mu.
+func (b *batcher) take() []Event {
+	batch := b.pending
+	b.pending = nil
+	b.keys = make(map[string]int)
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return batch
+}
+
+func (b *batcher) run() {
+	defer close(b.done)
+	b.runner.Store(goroutineID())
+
+	for batch := range b.flushes {
+		b.handle(batch)
+	}
+
+	// flushes is closed after the last leftover was added
+	for _, batch := range b.leftover {
+		b.handle(batch)
+	}
+}
+
+func (b *batcher) handle(batch []Event) {
+	var err error
+	for attempt := 1; attempt <= b.attempts; attempt++ {
+		if err = b.call(batch); err == nil {
+			return
+		}
+		if attempt == b.attempts || !b.wait() {
+			break
+		}
+	}
+
+	b.metrics.errors.Add(1)
+	if b.stats != nil {
+		b.stats.errors.Add(1)
+	}
+	if b.onError != nil {
+		b.onError(batch, err)
+		return
+	}
+	b.logger.LogError(context.Background(), "Batch handler failed", err, map[string]interface{}{
+		"topic":  b.topic,
+		"events": len(batch),
+	})
+}
+
+// wait sleeps for the retry backoff and reports false if the batcher is
+// stopped meanwhile
+func (b *batcher) wait() bool {
+	if b.backoff <= 0 {
+		select {
+		case <-b.quit:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(b.backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-b.quit:
+		return false
+	}
+}
+
+// call runs the handler once, under the bus default timeout if one is set
+func (b *batcher) call(batch []Event) error {
+	ctx := context.Background()
+	if b.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		defer cancel()
+	}
+
+	return b.handler(ctx, batch)
+}
+
+// stop flushes the pending events and waits until every batch is handled.
+// Called from the handler, which unsubscribes or closes the bus, it returns
+// without waiting since the batches can only be handled once the handler
+// returns. Other callers wait even while a batch is being handled.
+func (b *batcher) stop() {
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		b.waitDone()
+		return
+	}
+	b.stopped = true
+	batch := b.take()
+	b.mu.Unlock()
+
+	close(b.quit)
+	b.sending.Wait()
+
+	b.mu.Lock()
+	if len(batch) > 0 {
+		b.leftover = append(b.leftover, batch)
+	}
+	b.mu.Unlock()
+
+	close(b.flushes)
+	b.waitDone()
+}
+
+func (b *batcher) waitDone() {
+	if b.runner.Load() == goroutineID() {
+		return
+	}
+	<-b.done
+}
+
+// goroutineID returns the id of the calling goroutine, read from the header
+// of its stack trace: "goroutine 42 [running]:"
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(string(fields[1]), 10, 64)
+	return id
+}
@@ -0,0 +1,347 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type batchRecorder struct {
+	batches [][]Event
+	mu      sync.Mutex
+}
+
+func (r *batchRecorder) handle(ctx context.Context, events []Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, events)
+	return nil
+}
+
+func (r *batchRecorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sizes := make([]int, len(r.batches))
+	for i, batch := range r.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestSubscribeBatchFlushesWhenFull(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	recorder := &batchRecorder{}
+
+	if _, err := eventBus.SubscribeBatch("index.user", 3, time.Hour, recorder.handle); err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	for i := 0; i < 7; i++ {
+		eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if sizes := recorder.sizes(); len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 3 {
+		t.Errorf("Expected two full batches before close, got %v", sizes)
+	}
+
+	eventBus.Close()
+	if sizes := recorder.sizes(); len(sizes) != 3 || sizes[2] != 1 {
+		t.Errorf("Expected the remaining event to be flushed on close, got %v", sizes)
+	}
+}
+
+func TestSubscribeBatchFlushesAfterMaxWait(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	recorder := &batchRecorder{}
+
+	eventBus.SubscribeBatch("index.user", 100, 50*time.Millisecond, recorder.handle)
+
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+
+	time.Sleep(150 * time.Millisecond)
+	if sizes := recorder.sizes(); len(sizes) != 1 || sizes[0] != 2 {
+		t.Errorf("Expected one batch of 2 after max wait, got %v", sizes)
+	}
+}
+
+func TestSubscribeBatchDebounce(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	recorder := &batchRecorder{}
+
+	key := func(event Event) string {
+		return strconv.Itoa(event.(*UserCreatedEvent).UserID)
+	}
+	eventBus.SubscribeBatch("user.updated", 100, 50*time.Millisecond, recorder.handle, WithDebounce(key))
+
+	for i := 0; i < 3; i++ {
+		eventBus.Publish("user.updated", &UserCreatedEvent{BaseEvent: NewBaseEvent("user.updated"), UserID: 1, Username: strconv.Itoa(i)})
+		eventBus.Publish("user.updated", &UserCreatedEvent{BaseEvent: NewBaseEvent("user.updated"), UserID: 2, Username: strconv.Itoa(i)})
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.batches) != 1 || len(recorder.batches[0]) != 2 {
+		t.Fatalf("Expected one batch with one event per key, got %v", recorder.batches)
+	}
+	for _, event := range recorder.batches[0] {
+		if event.(*UserCreatedEvent).Username != "2" {
+			t.Errorf("Expected the latest event per key, got %+v", event)
+		}
+	}
+}
+
+func TestSubscribeBatchRetriesWholeBatch(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var mu sync.Mutex
+	attempts := 0
+	var failed []Event
+
+	handler := func(ctx context.Context, events []Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return errors.New("search cluster unavailable")
+	}
+	onError := func(events []Event, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failed = events
+	}
+
+	eventBus.SubscribeBatch("index.user", 2, time.Hour, handler,
+		WithBatchRetry(3, time.Millisecond),
+		WithBatchErrorHandler(onError),
+	)
+
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return failed != nil
+	})
+	eventBus.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if len(failed) != 2 {
+		t.Errorf("Expected the whole batch to be reported, got %d events", len(failed))
+	}
+	if errs := eventBus.Metrics().Errors; errs != 1 {
+		t.Errorf("Expected 1 batch error, got %d", errs)
+	}
+}
+
+func TestSubscribeBatchCloseInterruptsRetryBackoff(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var mu sync.Mutex
+	attempts := 0
+	var failed []Event
+	handler := func(ctx context.Context, events []Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return errors.New("search cluster unavailable")
+	}
+
+	eventBus.SubscribeBatch("index.user", 1, time.Hour, handler,
+		WithBatchRetry(5, time.Hour),
+		WithBatchErrorHandler(func(events []Event, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = events
+		}),
+	)
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts == 1
+	})
+
+	closed := make(chan struct{})
+	go func() {
+		eventBus.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close waited for the retry backoff")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 1 || len(failed) != 1 {
+		t.Errorf("Expected the batch to be reported after one attempt, got %d attempts and %d failed events", attempts, len(failed))
+	}
+}
+
+func TestSubscribeBatchLogsFailuresToBusLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	eventBus := NewEventBus(&EventBusConfig{Logger: logger})
+
+	eventBus.SubscribeBatch("index.user", 1, time.Hour, func(ctx context.Context, events []Event) error {
+		return errors.New("search cluster unavailable")
+	})
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	eventBus.Close()
+
+	if _, errs := logger.counts(); errs != 1 {
+		t.Errorf("Expected the failed batch on the bus logger, got %d errors", errs)
+	}
+}
+
+// returnsWithin fails the test unless fn returns within a second
+func returnsWithin(t *testing.T, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%s deadlocked", what)
+	}
+}
+
+func TestSubscribeBatchHandlerUnsubscribesItself(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	recorder := &batchRecorder{}
+
+	var sub Subscription
+	unsubscribed := make(chan error, 1)
+	sub, _ = eventBus.SubscribeBatch("index.user", 2, time.Hour, func(ctx context.Context, events []Event) error {
+		recorder.handle(ctx, events)
+		unsubscribed <- sub.Unsubscribe()
+		return nil
+	})
+
+	returnsWithin(t, "Publish", func() {
+		for i := 0; i < 3; i++ {
+			eventBus.Publish("index.user", NewBaseEvent("index.user"))
+		}
+	})
+
+	select {
+	case err := <-unsubscribed:
+		if err != nil {
+			t.Errorf("Unexpected error unsubscribing: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Unsubscribe from the batch handler deadlocked")
+	}
+
+	returnsWithin(t, "Close", func() { eventBus.Close() })
+	if eventBus.HasCallback("index.user") {
+		t.Error("Expected the subscription to be removed")
+	}
+	if sizes := recorder.sizes(); len(sizes) == 0 || sizes[0] != 2 {
+		t.Errorf("Expected the first batch to be handled, got %v", sizes)
+	}
+}
+
+func TestSubscribeBatchHandlerClosesBus(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	closed := make(chan error, 1)
+	eventBus.SubscribeBatch("shutdown", 1, time.Hour, func(ctx context.Context, events []Event) error {
+		closed <- eventBus.Close()
+		return nil
+	})
+	eventBus.Publish("shutdown", NewBaseEvent("shutdown"))
+
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Unexpected error closing: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close from the batch handler deadlocked")
+	}
+	if err := eventBus.Publish("shutdown", NewBaseEvent("shutdown")); err == nil {
+		t.Error("Expected the bus to be closed")
+	}
+}
+
+func TestSubscribeBatchCloseDuringSlowHandler(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	recorder := &batchRecorder{}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	eventBus.SubscribeBatch("index.user", 2, time.Hour, func(ctx context.Context, events []Event) error {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+		return recorder.handle(ctx, events)
+	})
+
+	for i := 0; i < 3; i++ {
+		eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	}
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		eventBus.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("Expected Close to wait for the batch being handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	returnsWithin(t, "Close", func() { <-closed })
+	if sizes := recorder.sizes(); len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 1 {
+		t.Errorf("Expected the leftover batch to be handled before Close returns, got %v", sizes)
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	if id == 0 {
+		t.Fatal("Expected a goroutine id")
+	}
+
+	other := make(chan uint64)
+	go func() { other <- goroutineID() }()
+	if got := <-other; got == 0 || got == id {
+		t.Errorf("Expected another goroutine to have its own id, got %d and %d", id, got)
+	}
+}
+
+func TestSubscribeBatchValidatesArguments(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	noop := func(ctx context.Context, events []Event) error { return nil }
+
+	if _, err := eventBus.SubscribeBatch("t", 0, time.Second, noop); err == nil {
+		t.Error("Expected error for non-positive batch size")
+	}
+	if _, err := eventBus.SubscribeBatch("t", 1, 0, noop); err == nil {
+		t.Error("Expected error for non-positive wait")
+	}
+	if _, err := eventBus.SubscribeBatch("t", 1, time.Second, nil); err == nil {
+		t.Error("Expected error for nil handler")
+	}
+}
@@ -6,115 +6,157 @@ import (
 )
 
 type ChannelSubscriber struct {
+	id      string
+	topic   string
+	bus     *eventBus
+	stats   subscriptionStats
 	channel chan Event
 	ctx     context.Context
 	cancel  context.CancelFunc
+	group   *consumerGroup
 	mu      sync.RWMutex
 	closed  bool
 }
 
+// ChannelEventBus adds channel subscribers to an EventBus. Channel
+// subscribers and func handlers share one dispatch path, so Publish and
+// PublishEvent both reach every subscriber of a topic.
 type ChannelEventBus struct {
 	EventBus
-	subscribers map[string][]*ChannelSubscriber
-	mu          sync.RWMutex
+	bus    *eventBus
+	groups map[string]map[string]*consumerGroup
+	mu     sync.Mutex
 }
 
 func NewChannelEventBus(config *EventBusConfig) *ChannelEventBus {
+	bus := NewEventBus(config).(*eventBus)
+
 	return &ChannelEventBus{
-		EventBus:    NewEventBus(config),
-		subscribers: make(map[string][]*ChannelSubscriber),
+		EventBus: bus,
+		bus:      bus,
+		groups:   make(map[string]map[string]*consumerGroup),
 	}
 }
 
 func (ceb *ChannelEventBus) SubscribeChannel(topic string, bufferSize int) *ChannelSubscriber {
-	if bufferSize <= 0 {
-		bufferSize = 100
+	return ceb.subscribeChannel(topic, bufferSize, nil)
+}
+
+// SubscribeChannelFiltered subscribes a channel that only receives events
+// accepted by predicate. Rejected events never take a buffer slot.
+func (ceb *ChannelEventBus) SubscribeChannelFiltered(topic string, bufferSize int, predicate EventPredicate) *ChannelSubscriber {
+	return ceb.subscribeChannel(topic, bufferSize, predicate)
+}
+
+// subscribeChannel registers a channel subscriber on the underlying bus.
+// On a closed bus the subscriber is returned already closed.
+func (ceb *ChannelEventBus) subscribeChannel(topic string, bufferSize int, filter EventPredicate) *ChannelSubscriber {
+	subscriber := newChannelSubscriber(topic, ceb.bufferSize(bufferSize))
+	subscriber.bus = ceb.bus
+
+	handler := &eventHandler{id: subscriber.id, filter: filter, sink: subscriber}
+	if _, err := ceb.bus.subscribe(topic, nil, handler); err != nil {
+		subscriber.close()
 	}
 
+	return subscriber
+}
+
+// bufferSize falls back to EventBusConfig.DefaultBufferSize
+func (ceb *ChannelEventBus) bufferSize(size int) int {
+	if size <= 0 {
+		return ceb.bus.config.DefaultBufferSize
+	}
+	return size
+}
+
+func newChannelSubscriber(topic string, bufferSize int) *ChannelSubscriber {
 	subscriber := &ChannelSubscriber{
+		id:      newSubscriptionID(),
+		topic:   topic,
 		channel: make(chan Event, bufferSize),
 	}
 	subscriber.ctx, subscriber.cancel = context.WithCancel(context.Background())
-
-	ceb.mu.Lock()
-	ceb.subscribers[topic] = append(ceb.subscribers[topic], subscriber)
-	ceb.mu.Unlock()
-
 	return subscriber
 }
 
+// PublishEvent publishes event on its name to channel subscribers and func
+// handlers alike
 func (ceb *ChannelEventBus) PublishEvent(ctx context.Context, event Event) error {
-	ceb.mu.RLock()
-	subscribers := ceb.subscribers[event.GetName()]
-	ceb.mu.RUnlock()
-
-	for _, subscriber := range subscribers {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-subscriber.ctx.Done():
-			continue
-		case subscriber.channel <- event:
-		default:
-		}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-
-	return nil
+	return ceb.Publish(event.GetName(), event)
 }
 
+// PublishEventAsync is like PublishEvent but runs func handlers on their own
+// goroutines. Channel subscribers never block, so they are served inline.
 func (ceb *ChannelEventBus) PublishEventAsync(ctx context.Context, event Event) {
-	ceb.mu.RLock()
-	subscribers := ceb.subscribers[event.GetName()]
-	ceb.mu.RUnlock()
-
-	for _, subscriber := range subscribers {
-		go func(sub *ChannelSubscriber) {
-			select {
-			case <-ctx.Done():
-				return
-			case <-sub.ctx.Done():
-				return
-			case sub.channel <- event:
-			default:
-			}
-		}(subscriber)
+	if ctx.Err() != nil {
+		return
 	}
+	ceb.PublishAsync(event.GetName(), event)
 }
 
-func (ceb *ChannelEventBus) UnsubscribeChannel(topic string, subscriber *ChannelSubscriber) {
-	ceb.mu.Lock()
-	defer ceb.mu.Unlock()
-
-	if subscribers, exists := ceb.subscribers[topic]; exists {
-		for i, sub := range subscribers {
-			if sub == subscriber {
-				ceb.subscribers[topic] = append(subscribers[:i], subscribers[i+1:]...)
-				subscriber.Close()
-				break
-			}
-		}
+// Unsubscribe removes a channel or handler subscription by ID
+func (ceb *ChannelEventBus) Unsubscribe(id string) error {
+	if member := ceb.groupMember(id); member != nil {
+		member.Close()
+		return nil
 	}
+	return ceb.EventBus.Unsubscribe(id)
+}
+
+func (ceb *ChannelEventBus) UnsubscribeChannel(topic string, subscriber *ChannelSubscriber) {
+	subscriber.Close()
 }
 
 func (ceb *ChannelEventBus) Close() error {
 	ceb.mu.Lock()
-	defer ceb.mu.Unlock()
-
-	for _, subscribers := range ceb.subscribers {
-		for _, subscriber := range subscribers {
-			subscriber.Close()
-		}
-	}
+	ceb.groups = make(map[string]map[string]*consumerGroup)
+	ceb.mu.Unlock()
 
-	ceb.subscribers = make(map[string][]*ChannelSubscriber)
 	return ceb.EventBus.Close()
 }
 
+func (cs *ChannelSubscriber) ID() string {
+	return cs.id
+}
+
+func (cs *ChannelSubscriber) Topic() string {
+	return cs.topic
+}
+
+// Unsubscribe removes the subscriber from its bus and closes its channel
+func (cs *ChannelSubscriber) Unsubscribe() error {
+	cs.Close()
+	return nil
+}
+
+func (cs *ChannelSubscriber) Stats() SubscriptionStats {
+	return cs.stats.snapshot()
+}
+
 func (cs *ChannelSubscriber) Channel() <-chan Event {
 	return cs.channel
 }
 
+// Close removes the subscriber from its bus and closes its channel. Group
+// members hand their undelivered events to the rest of the group first.
 func (cs *ChannelSubscriber) Close() {
+	switch {
+	case cs.group != nil:
+		cs.group.leave(cs)
+	case cs.bus != nil:
+		cs.bus.removeSubscription(cs.id)
+	}
+
+	cs.close()
+}
+
+// close closes the channel. Sends happen under the read lock, so no send
+// can race with it.
+func (cs *ChannelSubscriber) close() {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
 
@@ -125,6 +167,32 @@ func (cs *ChannelSubscriber) Close() {
 	}
 }
 
+// offer buffers event without blocking and records the outcome
+func (cs *ChannelSubscriber) offer(event Event) bool {
+	if cs.trySend(event) {
+		cs.stats.delivered.Add(1)
+		return true
+	}
+	cs.stats.dropped.Add(1)
+	return false
+}
+
+func (cs *ChannelSubscriber) trySend(event Event) bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	if cs.closed {
+		return false
+	}
+
+	select {
+	case cs.channel <- event:
+		return true
+	default:
+		return false
+	}
+}
+
 func (cs *ChannelSubscriber) IsClosed() bool {
 	cs.mu.RLock()
 	defer cs.mu.RUnlock()
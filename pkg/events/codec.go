@@ -0,0 +1,123 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RemoteEvent is delivered to handlers when an incoming message has no
+// registered event type. The original event body is kept in Payload.
+type RemoteEvent struct {
+	*BaseEvent
+	Payload json.RawMessage `json:"payload"`
+}
+
+// EventFactory returns an empty event value that a message payload is decoded into
+type EventFactory func() Event
+
+// envelope is the wire format shared by the transports that carry events
+// between processes
+type envelope struct {
+	Topic     string          `json:"topic"`
+	Name      string          `json:"name"`
+	ID        string          `json:"id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Ref       string          `json:"ref,omitempty"`
+}
+
+// eventCodec converts events to envelopes and back, using the registered
+// factories to restore their concrete types
+type eventCodec struct {
+	factories map[string]EventFactory
+	mu        sync.RWMutex
+}
+
+func newEventCodec() *eventCodec {
+	return &eventCodec{
+		factories: make(map[string]EventFactory),
+	}
+}
+
+func (c *eventCodec) register(topic string, factory EventFactory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.factories[topic] = factory
+}
+
+// eventArg extracts the single Event argument passed to Publish
+func eventArg(args ...interface{}) (Event, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected exactly one event argument, got %d", len(args))
+	}
+
+	event, ok := args[0].(Event)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement Event", args[0])
+	}
+	return event, nil
+}
+
+func (c *eventCodec) wrap(topic string, event Event) (envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return envelope{}, fmt.Errorf("failed to marshal event %s: %w", event.GetName(), err)
+	}
+
+	return envelope{
+		Topic:     topic,
+		Name:      event.GetName(),
+		ID:        event.GetID(),
+		Timestamp: event.GetTimestamp(),
+		Payload:   payload,
+	}, nil
+}
+
+func (c *eventCodec) encode(topic string, event Event) ([]byte, error) {
+	env, err := c.wrap(topic, event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(env)
+}
+
+func (c *eventCodec) unwrap(data []byte) (envelope, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return envelope{}, fmt.Errorf("failed to unmarshal event envelope: %w", err)
+	}
+	return env, nil
+}
+
+func (c *eventCodec) event(topic string, env envelope) (Event, error) {
+	c.mu.RLock()
+	factory, ok := c.factories[topic]
+	c.mu.RUnlock()
+
+	if !ok {
+		return &RemoteEvent{
+			BaseEvent: &BaseEvent{
+				Name:      env.Name,
+				ID:        env.ID,
+				Timestamp: env.Timestamp,
+			},
+			Payload: env.Payload,
+		}, nil
+	}
+
+	event := factory()
+	if err := json.Unmarshal(env.Payload, event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event %s: %w", env.Name, err)
+	}
+	return event, nil
+}
+
+func (c *eventCodec) decode(topic string, data []byte) (Event, error) {
+	env, err := c.unwrap(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.event(topic, env)
+}
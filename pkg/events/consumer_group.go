@@ -0,0 +1,242 @@
+package events
+
+import (
+	"sort"
+	"sync"
+)
+
+// GroupStrategy selects the member of a consumer group that receives an event
+type GroupStrategy int
+
+const (
+	// GroupRoundRobin hands events to the members in turn
+	GroupRoundRobin GroupStrategy = iota
+	// GroupLeastLoaded hands each event to the member with the fewest
+	// buffered events
+	GroupLeastLoaded
+)
+
+// consumerGroup delivers every event of a topic to exactly one of its
+// members. Members that are full are skipped; an event is dropped only when
+// every member is full. The group is registered on the bus as a single
+// subscriber once its first member joins and removed when the last one
+// leaves.
+type consumerGroup struct {
+	name       string
+	topic      string
+	owner      *ChannelEventBus
+	sinkID     string
+	strategy   GroupStrategy
+	members    []*ChannelSubscriber
+	next       int
+	subscribed bool
+	closed     bool
+	mu         sync.Mutex
+}
+
+// SubscribeChannelGroup joins a competing-consumer group on topic. Members
+// of the same group share the events of the topic instead of each receiving
+// a copy. Closing a member hands its undelivered events to the others.
+func (ceb *ChannelEventBus) SubscribeChannelGroup(topic, group string, bufferSize int) *ChannelSubscriber {
+	subscriber := newChannelSubscriber(topic, ceb.bufferSize(bufferSize))
+
+	ceb.mu.Lock()
+	defer ceb.mu.Unlock()
+
+	g := ceb.group(topic, group)
+	subscriber.group = g
+
+	g.mu.Lock()
+	g.members = append(g.members, subscriber)
+	subscribed := g.subscribed
+	g.subscribed = true
+	g.mu.Unlock()
+
+	if !subscribed {
+		sub, err := ceb.bus.subscribe(topic, nil, &eventHandler{sink: g})
+		if err != nil {
+			g.close()
+		} else {
+			g.mu.Lock()
+			g.sinkID = sub.ID()
+			g.mu.Unlock()
+		}
+	}
+
+	return subscriber
+}
+
+// SetGroupStrategy sets how a consumer group picks the member that receives
+// each event. Groups use GroupRoundRobin by default.
+func (ceb *ChannelEventBus) SetGroupStrategy(topic, group string, strategy GroupStrategy) {
+	ceb.mu.Lock()
+	g := ceb.group(topic, group)
+	ceb.mu.Unlock()
+
+	g.mu.Lock()
+	g.strategy = strategy
+	g.mu.Unlock()
+}
+
+// group returns the named group of topic, creating it if needed. Callers
+// must hold ceb.mu.
+func (ceb *ChannelEventBus) group(topic, name string) *consumerGroup {
+	groups, ok := ceb.groups[topic]
+	if !ok {
+		groups = make(map[string]*consumerGroup)
+		ceb.groups[topic] = groups
+	}
+
+	g, ok := groups[name]
+	if !ok {
+		g = &consumerGroup{name: name, topic: topic, owner: ceb}
+		groups[name] = g
+	}
+	return g
+}
+
+// releaseGroup unregisters g and removes it from the bus once its last
+// member has left. A member joining meanwhile keeps the group.
+func (ceb *ChannelEventBus) releaseGroup(g *consumerGroup) {
+	ceb.mu.Lock()
+	g.mu.Lock()
+	if len(g.members) > 0 || g.closed {
+		g.mu.Unlock()
+		ceb.mu.Unlock()
+		return
+	}
+	g.closed = true
+	sinkID := g.sinkID
+	g.mu.Unlock()
+
+	if groups := ceb.groups[g.topic]; groups[g.name] == g {
+		delete(groups, g.name)
+		if len(groups) == 0 {
+			delete(ceb.groups, g.topic)
+		}
+	}
+	ceb.mu.Unlock()
+
+	if sinkID != "" {
+		ceb.bus.removeSubscription(sinkID)
+	}
+}
+
+func (ceb *ChannelEventBus) groupMember(id string) *ChannelSubscriber {
+	ceb.mu.Lock()
+	defer ceb.mu.Unlock()
+
+	for _, groups := range ceb.groups {
+		for _, g := range groups {
+			if member := g.member(id); member != nil {
+				return member
+			}
+		}
+	}
+	return nil
+}
+
+// offer hands event to one member
+func (g *consumerGroup) offer(event Event) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.offerLocked(event)
+}
+
+// offerLocked offers event to the members in strategy order without
+// blocking and reports whether one accepted it. Callers must hold g.mu.
+func (g *consumerGroup) offerLocked(event Event) bool {
+	for _, member := range g.candidatesLocked() {
+		if member.trySend(event) {
+			member.stats.delivered.Add(1)
+			return true
+		}
+	}
+	return false
+}
+
+func (g *consumerGroup) candidatesLocked() []*ChannelSubscriber {
+	n := len(g.members)
+	if n == 0 {
+		return nil
+	}
+
+	start := g.next % n
+	g.next = (start + 1) % n
+
+	candidates := make([]*ChannelSubscriber, 0, n)
+	for i := 0; i < n; i++ {
+		candidates = append(candidates, g.members[(start+i)%n])
+	}
+
+	if g.strategy == GroupLeastLoaded {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return len(candidates[i].channel) < len(candidates[j].channel)
+		})
+	}
+	return candidates
+}
+
+// leave removes member from the group and rebalances the events still
+// buffered in its channel to the remaining members. Once leave returns the
+// group no longer sends to the member, so its channel can be closed. The
+// last member to leave releases the group.
+func (g *consumerGroup) leave(member *ChannelSubscriber) {
+	if g.remove(member) && g.owner != nil {
+		g.owner.releaseGroup(g)
+	}
+}
+
+// remove takes member out of the group and reports whether it was the last
+func (g *consumerGroup) remove(member *ChannelSubscriber) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	found := false
+	for i, m := range g.members {
+		if m == member {
+			g.members = append(g.members[:i:i], g.members[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found || g.closed {
+		return false
+	}
+
+	for {
+		select {
+		case event := <-member.channel:
+			if !g.offerLocked(event) {
+				member.stats.dropped.Add(1)
+			}
+		default:
+			return len(g.members) == 0
+		}
+	}
+}
+
+func (g *consumerGroup) member(id string) *ChannelSubscriber {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, member := range g.members {
+		if member.id == id {
+			return member
+		}
+	}
+	return nil
+}
+
+// close closes every member without rebalancing
+func (g *consumerGroup) close() {
+	g.mu.Lock()
+	g.closed = true
+	members := g.members
+	g.members = nil
+	g.mu.Unlock()
+
+	for _, member := range members {
+		member.close()
+	}
+}
@@ -0,0 +1,178 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func publishUsers(t *testing.T, bus *ChannelEventBus, from, to int) {
+	t.Helper()
+	for i := from; i <= to; i++ {
+		if err := bus.PublishEvent(context.Background(), &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: i}); err != nil {
+			t.Fatalf("Error publishing event: %v", err)
+		}
+	}
+}
+
+func drainUsers(subscriber *ChannelSubscriber) []int {
+	var ids []int
+	for {
+		select {
+		case event, ok := <-subscriber.Channel():
+			if !ok {
+				return ids
+			}
+			ids = append(ids, event.(*UserCreatedEvent).UserID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestChannelGroupRoundRobin(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+
+	first := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	second := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	other := bus.SubscribeChannelGroup("user.created", "audit", 10)
+	broadcast := bus.SubscribeChannel("user.created", 10)
+
+	publishUsers(t, bus, 1, 4)
+
+	firstIDs, secondIDs := drainUsers(first), drainUsers(second)
+	if len(firstIDs) != 2 || len(secondIDs) != 2 {
+		t.Errorf("Expected events to alternate between members, got %v and %v", firstIDs, secondIDs)
+	}
+	if got := drainUsers(other); len(got) != 4 {
+		t.Errorf("Expected the other group to receive every event once, got %v", got)
+	}
+	if got := drainUsers(broadcast); len(got) != 4 {
+		t.Errorf("Expected the plain subscriber to receive every event, got %v", got)
+	}
+}
+
+func TestChannelGroupSkipsFullMembers(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+
+	first := bus.SubscribeChannelGroup("user.created", "mailer", 1)
+	second := bus.SubscribeChannelGroup("user.created", "mailer", 1)
+
+	publishUsers(t, bus, 1, 3)
+
+	if got := len(drainUsers(first)) + len(drainUsers(second)); got != 2 {
+		t.Errorf("Expected 2 buffered events, got %d", got)
+	}
+	if metrics := bus.Metrics(); metrics.Delivered != 2 || metrics.Dropped != 1 {
+		t.Errorf("Unexpected metrics: %+v", metrics)
+	}
+}
+
+func TestChannelGroupLeastLoaded(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+	bus.SetGroupStrategy("user.created", "mailer", GroupLeastLoaded)
+
+	busy := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	publishUsers(t, bus, 1, 3)
+
+	idle := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	publishUsers(t, bus, 4, 6)
+
+	if got := drainUsers(busy); len(got) != 3 {
+		t.Errorf("Expected the busy member to keep its backlog only, got %v", got)
+	}
+	if got := drainUsers(idle); len(got) != 3 {
+		t.Errorf("Expected new events to go to the idle member, got %v", got)
+	}
+}
+
+func TestChannelGroupRebalancesOnLeave(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+
+	leaving := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	staying := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+
+	publishUsers(t, bus, 1, 6)
+	bus.UnsubscribeChannel("user.created", leaving)
+
+	if !leaving.IsClosed() {
+		t.Error("Expected the leaving member to be closed")
+	}
+	if got := drainUsers(leaving); len(got) != 0 {
+		t.Errorf("Expected the leaving member to be drained, got %v", got)
+	}
+
+	seen := make(map[int]bool)
+	for _, id := range drainUsers(staying) {
+		seen[id] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("Expected all 6 events on the remaining member, got %v", seen)
+	}
+}
+
+func TestChannelGroupConcurrentLeave(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+
+	members := make([]*ChannelSubscriber, 4)
+	for i := range members {
+		members[i] = bus.SubscribeChannelGroup("user.created", "mailer", 100)
+	}
+	last := bus.SubscribeChannelGroup("user.created", "mailer", 1000)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		publishUsers(t, bus, 1, 200)
+	}()
+	for _, member := range members {
+		wg.Add(1)
+		go func(member *ChannelSubscriber) {
+			defer wg.Done()
+			member.Close()
+		}(member)
+	}
+	wg.Wait()
+
+	if got := len(drainUsers(last)); got != 200 {
+		t.Errorf("Expected every event on the remaining member, got %d", got)
+	}
+}
+
+func TestChannelGroupReleasedWhenEmptyAndRejoined(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+
+	first := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	first.Close()
+
+	if bus.HasCallback("user.created") {
+		t.Error("Expected the group sink to be unregistered after the last member left")
+	}
+	bus.mu.Lock()
+	groups := len(bus.groups)
+	bus.mu.Unlock()
+	if groups != 0 {
+		t.Errorf("Expected the empty group to be removed, got %d topics", groups)
+	}
+
+	publishUsers(t, bus, 1, 2)
+	if metrics := bus.Metrics(); metrics.Dropped != 0 {
+		t.Errorf("Expected no events dropped by a released group, got %+v", metrics)
+	}
+
+	rejoined := bus.SubscribeChannelGroup("user.created", "mailer", 10)
+	publishUsers(t, bus, 3, 4)
+	if got := drainUsers(rejoined); len(got) != 2 || got[0] != 3 || got[1] != 4 {
+		t.Errorf("Expected the rejoined member to receive new events, got %v", got)
+	}
+	if info := bus.Inspect(); len(info.Topics) != 1 || len(info.Topics[0].Subscribers) != 1 {
+		t.Errorf("Expected a single group subscriber, got %+v", info.Topics)
+	}
+}
@@ -25,7 +25,7 @@ func TestEventBusBasic(t *testing.T) {
 		mu.Unlock()
 	}
 
-	err := eventBus.Subscribe("user.created", handler)
+	_, err := eventBus.Subscribe("user.created", handler)
 	if err != nil {
 		t.Errorf("Error subscribing: %v", err)
 	}
@@ -67,7 +67,7 @@ func TestEventBusSubscribeOnce(t *testing.T) {
 		mu.Unlock()
 	}
 
-	err := eventBus.SubscribeOnce("user.created", handler)
+	_, err := eventBus.SubscribeOnce("user.created", handler)
 	if err != nil {
 		t.Errorf("Error subscribing: %v", err)
 	}
@@ -102,7 +102,7 @@ func TestEventBusAsync(t *testing.T) {
 		wg.Done()
 	}
 
-	err := eventBus.SubscribeAsync("user.created", handler, false)
+	_, err := eventBus.SubscribeAsync("user.created", handler, false)
 	if err != nil {
 		t.Errorf("Error subscribing: %v", err)
 	}
@@ -184,7 +184,7 @@ func TestUnsubscribe(t *testing.T) {
 		mu.Unlock()
 	}
 
-	eventBus.Subscribe("test.event", handler)
+	sub, _ := eventBus.Subscribe("test.event", handler)
 
 	event := NewBaseEvent("test.event")
 	eventBus.Publish("test.event", event)
@@ -197,7 +197,7 @@ func TestUnsubscribe(t *testing.T) {
 	}
 	mu.Unlock()
 
-	err := eventBus.Unsubscribe("test.event", handler)
+	err := sub.Unsubscribe()
 	if err != nil {
 		t.Errorf("Error unsubscribing: %v", err)
 	}
@@ -313,3 +313,224 @@ func TestChannelEventBusAsync(t *testing.T) {
 	time.Sleep(200 * time.Millisecond)
 	subscriber.Close()
 }
+
+func TestSubscribeFiltered(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var received []int
+	predicate := func(event Event) bool {
+		return event.(*UserCreatedEvent).UserID%2 == 0
+	}
+
+	_, err := eventBus.SubscribeFiltered("user.created", predicate, func(event Event) {
+		received = append(received, event.(*UserCreatedEvent).UserID)
+	})
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	for i := 1; i <= 4; i++ {
+		eventBus.Publish("user.created", &UserCreatedEvent{
+			BaseEvent: NewBaseEvent("user.created"),
+			UserID:    i,
+		})
+	}
+
+	if len(received) != 2 || received[0] != 2 || received[1] != 4 {
+		t.Errorf("Expected only even user IDs, got %v", received)
+	}
+
+	metrics := eventBus.Metrics()
+	if metrics.FilterEvaluations != 4 || metrics.FilterRejections != 2 {
+		t.Errorf("Expected 4 evaluations and 2 rejections, got %+v", metrics)
+	}
+	if metrics.Published != 4 || metrics.Delivered != 2 {
+		t.Errorf("Expected 4 published and 2 delivered, got %+v", metrics)
+	}
+}
+
+func TestSubscribeFilteredRequiresPredicate(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	if _, err := eventBus.SubscribeFiltered("test.event", nil, func(event Event) {}); err == nil {
+		t.Error("Expected error for nil predicate")
+	}
+}
+
+func TestChannelEventBusFiltered(t *testing.T) {
+	channelEventBus := NewChannelEventBus(nil)
+	ctx := context.Background()
+
+	subscriber := channelEventBus.SubscribeChannelFiltered("user.created", 1, func(event Event) bool {
+		return event.(*UserCreatedEvent).Username == "jane_doe"
+	})
+
+	channelEventBus.PublishEvent(ctx, &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 1, Username: "john_doe"})
+	channelEventBus.PublishEvent(ctx, &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 2, Username: "jane_doe"})
+
+	select {
+	case event := <-subscriber.Channel():
+		if event.(*UserCreatedEvent).UserID != 2 {
+			t.Errorf("Expected only the matching event, got user %d", event.(*UserCreatedEvent).UserID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscriber didn't receive matching event")
+	}
+
+	metrics := channelEventBus.Metrics()
+	if metrics.FilterEvaluations != 2 || metrics.FilterRejections != 1 || metrics.Dropped != 0 {
+		t.Errorf("Unexpected metrics: %+v", metrics)
+	}
+
+	subscriber.Close()
+}
+
+func TestRetainedTopicReplaysToLateSubscribers(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	if err := eventBus.RetainTopic("config.changed", 2); err != nil {
+		t.Fatalf("Error retaining topic: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		eventBus.Publish("config.changed", &UserCreatedEvent{BaseEvent: NewBaseEvent("config.changed"), UserID: i})
+	}
+
+	var received []int
+	eventBus.Subscribe("config.changed", func(event Event) {
+		received = append(received, event.(*UserCreatedEvent).UserID)
+	})
+
+	if len(received) != 2 || received[0] != 2 || received[1] != 3 {
+		t.Errorf("Expected the last 2 events in order, got %v", received)
+	}
+
+	last, ok := eventBus.Last("config.changed")
+	if !ok || last.(*UserCreatedEvent).UserID != 3 {
+		t.Errorf("Expected Last to return the newest event, got %v", last)
+	}
+
+	if _, ok := eventBus.Last("not.retained"); ok {
+		t.Error("Expected no last event for a topic that isn't retained")
+	}
+}
+
+func TestRetainedTopicFromConfig(t *testing.T) {
+	eventBus := NewEventBus(&EventBusConfig{RetainedTopics: map[string]int{"flags.toggled": 1}})
+
+	eventBus.Publish("flags.toggled", NewBaseEvent("flags.toggled"))
+	eventBus.Publish("flags.toggled", NewBaseEvent("flags.toggled"))
+
+	count := 0
+	eventBus.SubscribeOnce("flags.toggled", func(event Event) {
+		count++
+	})
+
+	if count != 1 {
+		t.Errorf("Expected one replayed event, got %d", count)
+	}
+	if eventBus.HasCallback("flags.toggled") {
+		t.Error("Expected once handler to be removed after replay")
+	}
+}
+
+func TestChannelEventBusRetainedTopic(t *testing.T) {
+	channelEventBus := NewChannelEventBus(nil)
+	channelEventBus.RetainTopic("user.created", 1)
+	ctx := context.Background()
+
+	channelEventBus.PublishEvent(ctx, &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 1})
+	channelEventBus.PublishEvent(ctx, &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 2})
+
+	subscriber := channelEventBus.SubscribeChannel("user.created", 10)
+
+	select {
+	case event := <-subscriber.Channel():
+		if event.(*UserCreatedEvent).UserID != 2 {
+			t.Errorf("Expected the latest event, got user %d", event.(*UserCreatedEvent).UserID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscriber didn't receive retained event")
+	}
+
+	last, ok := channelEventBus.Last("user.created")
+	if !ok || last.(*UserCreatedEvent).UserID != 2 {
+		t.Errorf("Expected Last to return the newest event, got %v", last)
+	}
+
+	subscriber.Close()
+}
+
+func TestChannelEventBusUnifiedDelivery(t *testing.T) {
+	channelEventBus := NewChannelEventBus(nil)
+	ctx := context.Background()
+
+	subscriber := channelEventBus.SubscribeChannel("user.created", 10)
+
+	var handled int
+	channelEventBus.Subscribe("user.created", func(event Event) {
+		handled++
+	})
+
+	channelEventBus.Publish("user.created", NewBaseEvent("user.created"))
+	channelEventBus.PublishEvent(ctx, NewBaseEvent("user.created"))
+
+	if handled != 2 {
+		t.Errorf("Expected the func handler to receive both events, got %d", handled)
+	}
+	if len(subscriber.Channel()) != 2 {
+		t.Errorf("Expected the channel to receive both events, got %d", len(subscriber.Channel()))
+	}
+
+	metrics := channelEventBus.Metrics()
+	if metrics.Published != 2 || metrics.Delivered != 4 {
+		t.Errorf("Unexpected metrics: %+v", metrics)
+	}
+
+	channelEventBus.PublishEventAsync(ctx, NewBaseEvent("user.created"))
+	channelEventBus.WaitAsync()
+	if handled != 3 || len(subscriber.Channel()) != 3 {
+		t.Errorf("Expected async publish to reach both subscribers, got %d and %d", handled, len(subscriber.Channel()))
+	}
+}
+
+func TestChannelEventBusClosed(t *testing.T) {
+	channelEventBus := NewChannelEventBus(nil)
+	subscriber := channelEventBus.SubscribeChannel("user.created", 10)
+
+	if err := channelEventBus.Close(); err != nil {
+		t.Fatalf("Error closing bus: %v", err)
+	}
+
+	if !subscriber.IsClosed() {
+		t.Error("Expected channel subscribers to be closed with the bus")
+	}
+	if err := channelEventBus.PublishEvent(context.Background(), NewBaseEvent("user.created")); err == nil {
+		t.Error("Expected error publishing on a closed bus")
+	}
+	if late := channelEventBus.SubscribeChannel("user.created", 10); !late.IsClosed() {
+		t.Error("Expected subscribers of a closed bus to be closed")
+	}
+}
+
+func TestChannelEventBusCloseDuringPublish(t *testing.T) {
+	channelEventBus := NewChannelEventBus(nil)
+	subscriber := channelEventBus.SubscribeChannel("user.created", 1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			channelEventBus.PublishEventAsync(context.Background(), NewBaseEvent("user.created"))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		subscriber.Close()
+	}()
+	wg.Wait()
+
+	if channelEventBus.HasCallback("user.created") {
+		t.Error("Expected closed subscriber to be removed from the bus")
+	}
+}
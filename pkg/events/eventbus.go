@@ -1,49 +1,105 @@
 package events
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type EventBus interface {
-	Subscribe(topic string, handler interface{}) error
-	SubscribeOnce(topic string, handler interface{}) error
-	SubscribeAsync(topic string, handler interface{}, transactional bool) error
-	SubscribeOnceAsync(topic string, handler interface{}) error
-	Unsubscribe(topic string, handler interface{}) error
+	Subscribe(topic string, handler interface{}, opts ...SubscribeOption) (Subscription, error)
+	SubscribeOnce(topic string, handler interface{}, opts ...SubscribeOption) (Subscription, error)
+	SubscribeAsync(topic string, handler interface{}, transactional bool, opts ...SubscribeOption) (Subscription, error)
+	SubscribeOnceAsync(topic string, handler interface{}, opts ...SubscribeOption) (Subscription, error)
+	SubscribeFiltered(topic string, predicate EventPredicate, handler interface{}, opts ...SubscribeOption) (Subscription, error)
+	SubscribeBatch(topic string, maxSize int, maxWait time.Duration, handler BatchHandler, opts ...BatchOption) (Subscription, error)
+	// Unsubscribe removes the subscription with the given ID
+	Unsubscribe(id string) error
+	// UnsubscribeHandler removes the subscription of handler on topic.
+	//
+	// Deprecated: use Unsubscribe with the ID of the Subscription returned
+	// by Subscribe, or Subscription.Unsubscribe.
+	UnsubscribeHandler(topic string, handler interface{}) error
 	Publish(topic string, args ...interface{}) error
 	PublishAsync(topic string, args ...interface{})
 	HasCallback(topic string) bool
+	RetainTopic(topic string, limit int) error
+	Last(topic string) (Event, bool)
 	WaitAsync()
+	Metrics() Metrics
+	// Inspect returns the current topics, subscribers and counters
+	Inspect() BusInfo
 	Close() error
 }
 
 type eventBus struct {
-	handlers map[string][]*eventHandler
-	mu       sync.RWMutex
-	wg       sync.WaitGroup
-	closed   bool
-	closeMu  sync.RWMutex
+	config        *EventBusConfig
+	logger        Logger
+	handlers      map[string][]*eventHandler
+	subscriptions map[string]*eventHandler
+	mu            sync.RWMutex
+	wg            sync.WaitGroup
+	closed        bool
+	closeMu       sync.RWMutex
+	metrics       busMetrics
+	retained      *retention
+	batchers      []*batcher
 }
 
 type eventHandler struct {
+	id            string
+	topic         string
+	bus           *eventBus
 	callBack      reflect.Value
+	withContext   bool
+	timeout       time.Duration
+	slowThreshold time.Duration
 	once          bool
 	async         bool
 	transactional bool
+	filter        EventPredicate
+	batcher       *batcher
+	sink          eventSink
+	removed       atomic.Bool
+	inFlight      atomic.Int64
+	stats         subscriptionStats
+}
+
+// eventSink receives events in place of a callback. Sinks never block, so
+// they are always served on the publishing goroutine.
+type eventSink interface {
+	// offer delivers event and reports whether it was accepted
+	offer(event Event) bool
+	describe(info *SubscriberInfo)
+	close()
 }
 
 type EventBusConfig struct {
+	// DefaultBufferSize is the channel buffer of channel subscribers that
+	// don't set one
 	DefaultBufferSize int
-	DefaultTimeout    time.Duration
+	// DefaultTimeout is the deadline of handlers without their own timeout.
+	// Zero disables the deadline. The deadline does not stop a handler; see
+	// WithTimeout.
+	DefaultTimeout time.Duration
+	// SlowHandlerThreshold reports handlers that finish in time but take
+	// longer than this. Zero disables the warning.
+	SlowHandlerThreshold time.Duration
+	// Logger receives handler timeouts and slow handler warnings. The
+	// global zerolog logger is used when it is nil.
+	Logger Logger
+	// RetainedTopics maps topics to the number of events replayed to new subscribers
+	RetainedTopics map[string]int
 }
 
 func DefaultConfig() *EventBusConfig {
 	return &EventBusConfig{
-		DefaultBufferSize: 100,
-		DefaultTimeout:    30 * time.Second,
+		DefaultBufferSize:    100,
+		DefaultTimeout:       30 * time.Second,
+		SlowHandlerThreshold: 5 * time.Second,
 	}
 }
 
@@ -51,55 +107,142 @@ func NewEventBus(config *EventBusConfig) EventBus {
 	if config == nil {
 		config = DefaultConfig()
 	}
+	if config.DefaultBufferSize <= 0 {
+		cfg := *config
+		cfg.DefaultBufferSize = DefaultConfig().DefaultBufferSize
+		config = &cfg
+	}
 
 	return &eventBus{
-		handlers: make(map[string][]*eventHandler),
+		config:        config,
+		logger:        loggerOrDefault(config.Logger),
+		handlers:      make(map[string][]*eventHandler),
+		subscriptions: make(map[string]*eventHandler),
+		retained:      newRetention(config.RetainedTopics),
 	}
 }
 
-func (bus *eventBus) Subscribe(topic string, fn interface{}) error {
-	return bus.subscribe(topic, fn, false, false, false)
+// Subscribe registers a handler for topic. Handlers take the published
+// arguments, optionally preceded by a context.Context that is cancelled when
+// the handler's deadline passes.
+func (bus *eventBus) Subscribe(topic string, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	return bus.subscribe(topic, fn, newEventHandler(opts))
+}
+
+func (bus *eventBus) SubscribeOnce(topic string, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	h := newEventHandler(opts)
+	h.once = true
+	return bus.subscribe(topic, fn, h)
+}
+
+func (bus *eventBus) SubscribeAsync(topic string, fn interface{}, transactional bool, opts ...SubscribeOption) (Subscription, error) {
+	h := newEventHandler(opts)
+	h.async = true
+	h.transactional = transactional
+	return bus.subscribe(topic, fn, h)
 }
 
-func (bus *eventBus) SubscribeOnce(topic string, fn interface{}) error {
-	return bus.subscribe(topic, fn, true, false, false)
+func (bus *eventBus) SubscribeOnceAsync(topic string, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	h := newEventHandler(opts)
+	h.once = true
+	h.async = true
+	return bus.subscribe(topic, fn, h)
 }
 
-func (bus *eventBus) SubscribeAsync(topic string, fn interface{}, transactional bool) error {
-	return bus.subscribe(topic, fn, false, true, transactional)
+// SubscribeFiltered subscribes a handler that only receives events accepted
+// by predicate. The predicate runs on the publishing goroutine before the
+// handler is dispatched.
+func (bus *eventBus) SubscribeFiltered(topic string, predicate EventPredicate, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	if predicate == nil {
+		return nil, fmt.Errorf("predicate is required for filtered subscriptions")
+	}
+	h := newEventHandler(opts)
+	h.filter = predicate
+	return bus.subscribe(topic, fn, h)
 }
 
-func (bus *eventBus) SubscribeOnceAsync(topic string, fn interface{}) error {
-	return bus.subscribe(topic, fn, true, true, false)
+// SubscribeBatch delivers events of topic in batches of up to maxSize,
+// flushing a partial batch once maxWait has passed since its first event and
+// on Close and on Unsubscribe. A failed batch is retried as a whole when
+// WithBatchRetry is set.
+func (bus *eventBus) SubscribeBatch(topic string, maxSize int, maxWait time.Duration, handler BatchHandler, opts ...BatchOption) (Subscription, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("batch handler is required")
+	}
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", maxSize)
+	}
+	if maxWait <= 0 {
+		return nil, fmt.Errorf("batch wait must be positive, got %s", maxWait)
+	}
+
+	b := newBatcher(topic, maxSize, maxWait, handler, &bus.metrics, opts...)
+	b.timeout = bus.config.DefaultTimeout
+	b.logger = bus.logger
+	h := &eventHandler{batcher: b}
+	b.stats = &h.stats
+
+	sub, err := bus.subscribe(topic, b.add, h)
+	if err != nil {
+		b.stop()
+		return nil, err
+	}
+	return sub, nil
 }
 
-func (bus *eventBus) subscribe(topic string, fn interface{}, once, async, transactional bool) error {
+// subscribe registers handler, whose delivery options are already set, for
+// topic and replays the retained events to it
+func (bus *eventBus) subscribe(topic string, fn interface{}, handler *eventHandler) (Subscription, error) {
 	bus.closeMu.RLock()
 	if bus.closed {
 		bus.closeMu.RUnlock()
-		return fmt.Errorf("eventbus is closed")
+		return nil, fmt.Errorf("eventbus is closed")
 	}
 	bus.closeMu.RUnlock()
 
-	if reflect.TypeOf(fn).Kind() != reflect.Func {
-		return fmt.Errorf("%s is not of type reflect.Func", reflect.TypeOf(fn))
+	if handler.sink == nil {
+		if reflect.TypeOf(fn).Kind() != reflect.Func {
+			return nil, fmt.Errorf("%s is not of type reflect.Func", reflect.TypeOf(fn))
+		}
+		handler.callBack = reflect.ValueOf(fn)
+		handler.withContext = acceptsContext(handler.callBack.Type())
 	}
 
-	bus.mu.Lock()
-	defer bus.mu.Unlock()
-
-	handler := &eventHandler{
-		callBack:      reflect.ValueOf(fn),
-		once:          once,
-		async:         async,
-		transactional: transactional,
+	if handler.id == "" {
+		handler.id = newSubscriptionID()
 	}
+	handler.topic = topic
+	handler.bus = bus
 
+	bus.mu.Lock()
 	bus.handlers[topic] = append(bus.handlers[topic], handler)
-	return nil
+	bus.subscriptions[handler.id] = handler
+	if handler.batcher != nil {
+		bus.batchers = append(bus.batchers, handler.batcher)
+	}
+	replay := bus.retained.snapshot(topic)
+	bus.mu.Unlock()
+
+	bus.replay(handler, replay)
+	return handler, nil
+}
+
+// replay delivers retained events to a new handler, oldest first
+func (bus *eventBus) replay(handler *eventHandler, retained []Event) {
+	for _, event := range retained {
+		if !bus.accepts(handler, event) {
+			continue
+		}
+		bus.dispatch(handler, handler.async, event)
+		if handler.once {
+			return
+		}
+	}
 }
 
-func (bus *eventBus) Unsubscribe(topic string, fn interface{}) error {
+// Unsubscribe removes a subscription by ID. It is safe to call while events
+// are being published, including from inside a handler.
+func (bus *eventBus) Unsubscribe(id string) error {
 	bus.closeMu.RLock()
 	if bus.closed {
 		bus.closeMu.RUnlock()
@@ -107,22 +250,56 @@ func (bus *eventBus) Unsubscribe(topic string, fn interface{}) error {
 	}
 	bus.closeMu.RUnlock()
 
-	bus.mu.Lock()
-	defer bus.mu.Unlock()
+	if !bus.removeSubscription(id) {
+		return fmt.Errorf("subscription %s not found", id)
+	}
+	return nil
+}
 
-	if _, ok := bus.handlers[topic]; !ok {
-		return fmt.Errorf("topic %s doesn't exist", topic)
+// UnsubscribeHandler removes the first subscription of fn on topic. It keeps
+// the topic and handler form that Unsubscribe had before subscriptions were
+// identified by ID.
+//
+// Deprecated: use Unsubscribe with the ID of the Subscription returned by
+// Subscribe, or Subscription.Unsubscribe.
+func (bus *eventBus) UnsubscribeHandler(topic string, fn interface{}) error {
+	id, err := bus.handlerID(topic, fn)
+	if err != nil {
+		return err
 	}
+	return bus.Unsubscribe(id)
+}
 
+// handlerID returns the ID of the first subscription of fn on topic
+func (bus *eventBus) handlerID(topic string, fn interface{}) (string, error) {
 	rv := reflect.ValueOf(fn)
-	for i, handler := range bus.handlers[topic] {
-		if handler.callBack == rv {
-			bus.handlers[topic] = append(bus.handlers[topic][:i], bus.handlers[topic][i+1:]...)
-			return nil
+
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+
+	handlers, ok := bus.handlers[topic]
+	if !ok {
+		return "", fmt.Errorf("topic %s doesn't exist", topic)
+	}
+	for _, handler := range handlers {
+		if handler.sink == nil && handler.batcher == nil && handler.callBack == rv {
+			return handler.id, nil
 		}
 	}
+	return "", fmt.Errorf("handler not found for topic %s", topic)
+}
+
+// removeSubscription removes a subscription by ID and reports whether it
+// was registered
+func (bus *eventBus) removeSubscription(id string) bool {
+	bus.mu.RLock()
+	handler, ok := bus.subscriptions[id]
+	bus.mu.RUnlock()
 
-	return fmt.Errorf("handler not found for topic %s", topic)
+	if ok {
+		bus.removeHandler(handler)
+	}
+	return ok
 }
 
 func (bus *eventBus) Publish(topic string, args ...interface{}) error {
@@ -136,17 +313,18 @@ func (bus *eventBus) Publish(topic string, args ...interface{}) error {
 	bus.mu.RLock()
 	handlers := make([]*eventHandler, len(bus.handlers[topic]))
 	copy(handlers, bus.handlers[topic])
+	// Recording under the handlers lock ensures a concurrent subscriber
+	// either receives the event directly or through the replay
+	bus.retained.recordArgs(topic, args...)
 	bus.mu.RUnlock()
 
-	if len(handlers) > 0 {
-		for _, handler := range handlers {
-			if handler.async {
-				bus.wg.Add(1)
-				go bus.executeHandler(handler, args...)
-			} else {
-				bus.executeHandler(handler, args...)
-			}
+	bus.metrics.published.Add(1)
+
+	for _, handler := range handlers {
+		if !bus.accepts(handler, args...) {
+			continue
 		}
+		bus.dispatch(handler, handler.async, args...)
 	}
 	return nil
 }
@@ -162,23 +340,57 @@ func (bus *eventBus) PublishAsync(topic string, args ...interface{}) {
 	bus.mu.RLock()
 	handlers := make([]*eventHandler, len(bus.handlers[topic]))
 	copy(handlers, bus.handlers[topic])
+	bus.retained.recordArgs(topic, args...)
 	bus.mu.RUnlock()
 
-	if len(handlers) > 0 {
-		for _, handler := range handlers {
-			bus.wg.Add(1)
-			go bus.executeHandler(handler, args...)
+	bus.metrics.published.Add(1)
+
+	for _, handler := range handlers {
+		if !bus.accepts(handler, args...) {
+			continue
 		}
+		bus.dispatch(handler, handler.sink == nil, args...)
 	}
 }
 
-func (bus *eventBus) executeHandler(handler *eventHandler, args ...interface{}) {
-	if handler.async {
+// dispatch is the single delivery path of the bus. Async deliveries run on
+// their own goroutine and are tracked by WaitAsync.
+func (bus *eventBus) dispatch(handler *eventHandler, async bool, args ...interface{}) {
+	handler.inFlight.Add(1)
+	if !async {
+		defer handler.inFlight.Add(-1)
+		bus.executeHandler(handler, args...)
+		return
+	}
+
+	bus.wg.Add(1)
+	go func() {
 		defer bus.wg.Done()
+		defer handler.inFlight.Add(-1)
+		bus.executeHandler(handler, args...)
+	}()
+}
+
+func (bus *eventBus) executeHandler(handler *eventHandler, args ...interface{}) {
+	if handler.sink != nil {
+		bus.offer(handler, args...)
+		return
+	}
+
+	// Publishers work on a copy of the handlers, so a handler removed in the
+	// meantime is skipped here. Once handlers claim their single delivery.
+	if handler.once {
+		if !handler.removed.CompareAndSwap(false, true) {
+			return
+		}
+	} else if handler.removed.Load() {
+		return
 	}
 
-	passedArguments := bus.setUpPublish(handler.callBack, args...)
-	handler.callBack.Call(passedArguments)
+	if bus.invoke(handler, args...) {
+		bus.metrics.delivered.Add(1)
+		handler.stats.delivered.Add(1)
+	}
 
 	if handler.once {
 		if handler.async {
@@ -189,29 +401,93 @@ func (bus *eventBus) executeHandler(handler *eventHandler, args ...interface{})
 	}
 }
 
+// offer hands the published event to a sink handler
+func (bus *eventBus) offer(handler *eventHandler, args ...interface{}) {
+	if handler.removed.Load() {
+		return
+	}
+
+	event, err := eventArg(args...)
+	if err == nil && handler.sink.offer(event) {
+		bus.metrics.delivered.Add(1)
+		return
+	}
+	bus.metrics.dropped.Add(1)
+}
+
+// accepts reports whether the handler's filter, if any, accepts the event
+// passed as the first publish argument
+func (bus *eventBus) accepts(handler *eventHandler, args ...interface{}) bool {
+	if handler.filter == nil {
+		return true
+	}
+
+	var event Event
+	if len(args) > 0 {
+		event, _ = args[0].(Event)
+	}
+	return bus.metrics.evaluate(handler.filter, event)
+}
+
+// removeHandler detaches handler from the bus. The handler lists are
+// replaced rather than modified in place so that copies taken by concurrent
+// publishers stay intact. A batch subscription flushes its pending events.
 func (bus *eventBus) removeHandler(handler *eventHandler) {
+	handler.removed.Store(true)
+
 	bus.mu.Lock()
-	defer bus.mu.Unlock()
+	if _, ok := bus.subscriptions[handler.id]; !ok {
+		bus.mu.Unlock()
+		return
+	}
+	delete(bus.subscriptions, handler.id)
+
+	handlers := make([]*eventHandler, 0, len(bus.handlers[handler.topic]))
+	for _, h := range bus.handlers[handler.topic] {
+		if h != handler {
+			handlers = append(handlers, h)
+		}
+	}
+	if len(handlers) == 0 {
+		delete(bus.handlers, handler.topic)
+	} else {
+		bus.handlers[handler.topic] = handlers
+	}
 
-	for topic, handlers := range bus.handlers {
-		for i, h := range handlers {
-			if h == handler {
-				bus.handlers[topic] = append(handlers[:i], handlers[i+1:]...)
-				return
+	if handler.batcher != nil {
+		batchers := make([]*batcher, 0, len(bus.batchers))
+		for _, b := range bus.batchers {
+			if b != handler.batcher {
+				batchers = append(batchers, b)
 			}
 		}
+		bus.batchers = batchers
+	}
+	bus.mu.Unlock()
+
+	if handler.batcher != nil {
+		handler.batcher.stop()
+	}
+	if handler.sink != nil {
+		handler.sink.close()
 	}
 }
 
-func (bus *eventBus) setUpPublish(function reflect.Value, args ...interface{}) []reflect.Value {
-	funcType := function.Type()
-	passedArguments := make([]reflect.Value, len(args))
+func (bus *eventBus) setUpPublish(ctx context.Context, handler *eventHandler, args ...interface{}) []reflect.Value {
+	funcType := handler.callBack.Type()
+	passedArguments := make([]reflect.Value, 0, len(args)+1)
+
+	offset := 0
+	if handler.withContext {
+		passedArguments = append(passedArguments, reflect.ValueOf(&ctx).Elem())
+		offset = 1
+	}
 
 	for i, v := range args {
 		if v == nil {
-			passedArguments[i] = reflect.New(funcType.In(i)).Elem()
+			passedArguments = append(passedArguments, reflect.New(funcType.In(i+offset)).Elem())
 		} else {
-			passedArguments[i] = reflect.ValueOf(v)
+			passedArguments = append(passedArguments, reflect.ValueOf(v))
 		}
 	}
 
@@ -226,24 +502,82 @@ func (bus *eventBus) HasCallback(topic string) bool {
 	return ok && len(bus.handlers[topic]) > 0
 }
 
+// RetainTopic keeps the last limit events of topic and replays them to
+// every new subscriber. A limit of zero stops retaining the topic.
+func (bus *eventBus) RetainTopic(topic string, limit int) error {
+	return bus.retained.retain(topic, limit)
+}
+
+// Last returns the most recent retained event of topic
+func (bus *eventBus) Last(topic string) (Event, bool) {
+	return bus.retained.last(topic)
+}
+
 func (bus *eventBus) WaitAsync() {
 	bus.wg.Wait()
 }
 
+func (bus *eventBus) Metrics() Metrics {
+	return bus.metrics.snapshot()
+}
+
 func (bus *eventBus) Close() error {
 	bus.closeMu.Lock()
-	defer bus.closeMu.Unlock()
-
 	if bus.closed {
+		bus.closeMu.Unlock()
 		return nil
 	}
-
 	bus.closed = true
+	bus.closeMu.Unlock()
+
+	// Async handlers may still publish while they drain; those publishes
+	// are rejected instead of blocking on the close
 	bus.WaitAsync()
 
 	bus.mu.Lock()
+	batchers := bus.batchers
+	var sinks []eventSink
+	for _, handler := range bus.subscriptions {
+		handler.removed.Store(true)
+		if handler.sink != nil {
+			sinks = append(sinks, handler.sink)
+		}
+	}
+	bus.batchers = nil
 	bus.handlers = make(map[string][]*eventHandler)
+	bus.subscriptions = make(map[string]*eventHandler)
 	bus.mu.Unlock()
 
+	for _, b := range batchers {
+		b.stop()
+	}
+	for _, sink := range sinks {
+		sink.close()
+	}
+
 	return nil
 }
+
+func newEventHandler(opts []SubscribeOption) *eventHandler {
+	h := &eventHandler{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *eventHandler) ID() string {
+	return h.id
+}
+
+func (h *eventHandler) Topic() string {
+	return h.topic
+}
+
+func (h *eventHandler) Unsubscribe() error {
+	return h.bus.Unsubscribe(h.id)
+}
+
+func (h *eventHandler) Stats() SubscriptionStats {
+	return h.stats.snapshot()
+}
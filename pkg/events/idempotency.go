@@ -0,0 +1,281 @@
+package events
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProcessedEventStore records which events each listener has handled
+type ProcessedEventStore interface {
+	// Process runs fn unless the (listener, eventID) pair was already
+	// recorded, and records it together with fn's side effects. It reports
+	// whether fn ran.
+	Process(ctx context.Context, listener, eventID string, fn func(ctx context.Context) error) (bool, error)
+	// DeleteBefore removes records processed before the given time
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+// IdempotentListener wraps a Listener so that redelivered events are
+// skipped instead of being handled again
+type IdempotentListener struct {
+	name     string
+	listener Listener
+	store    ProcessedEventStore
+}
+
+// NewIdempotentListener creates an IdempotentListener. The name identifies
+// the listener in the store and must be stable across restarts.
+func NewIdempotentListener(name string, listener Listener, store ProcessedEventStore) *IdempotentListener {
+	return &IdempotentListener{
+		name:     name,
+		listener: listener,
+		store:    store,
+	}
+}
+
+func (l *IdempotentListener) Handle(ctx context.Context, event Event) error {
+	_, err := l.store.Process(ctx, l.name, event.GetID(), func(ctx context.Context) error {
+		return l.listener.Handle(ctx, event)
+	})
+	return err
+}
+
+// StartProcessedEventCleanup periodically removes records older than ttl
+// until ctx is cancelled. Failures go to logger, or to the global zerolog
+// logger when it is nil.
+func StartProcessedEventCleanup(ctx context.Context, store ProcessedEventStore, interval, ttl time.Duration, logger Logger) {
+	logger = loggerOrDefault(logger)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := store.DeleteBefore(ctx, time.Now().Add(-ttl)); err != nil {
+					logger.LogError(ctx, "Failed to clean up processed events", err)
+				}
+			}
+		}
+	}()
+}
+
+type txKey struct{}
+
+// WithTx stores a database transaction in ctx so that listeners and the
+// processed-event store share it
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored by WithTx, if any
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// ProcessedEvent is a (listener, event ID) pair that has been handled
+type ProcessedEvent struct {
+	Listener    string    `gorm:"primaryKey;size:255"`
+	EventID     string    `gorm:"primaryKey;size:255"`
+	ProcessedAt time.Time `gorm:"index;not null"`
+}
+
+// TableName returns the table name for the ProcessedEvent entity
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+// GormProcessedEventStore keeps processed events in a database table
+type GormProcessedEventStore struct {
+	db *gorm.DB
+}
+
+// NewGormProcessedEventStore creates a GormProcessedEventStore and migrates its table
+func NewGormProcessedEventStore(db *gorm.DB) (*GormProcessedEventStore, error) {
+	if err := db.AutoMigrate(&ProcessedEvent{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate processed events: %w", err)
+	}
+	return &GormProcessedEventStore{db: db}, nil
+}
+
+// Process records the event and runs fn in one transaction. When ctx holds
+// a transaction it is joined through a savepoint, otherwise a new one is
+// started; fn receives a context carrying that transaction.
+func (s *GormProcessedEventStore) Process(ctx context.Context, listener, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	db := s.db
+	if tx, ok := TxFromContext(ctx); ok {
+		db = tx
+	}
+
+	processed := false
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedEvent{
+			Listener:    listener,
+			EventID:     eventID,
+			ProcessedAt: time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		processed = true
+		return fn(WithTx(ctx, tx))
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return processed, nil
+}
+
+func (s *GormProcessedEventStore) DeleteBefore(ctx context.Context, before time.Time) error {
+	return s.db.WithContext(ctx).Where("processed_at < ?", before).Delete(&ProcessedEvent{}).Error
+}
+
+// MemoryProcessedEventStore keeps the most recently processed events in
+// memory, evicting the least recently used entries beyond its capacity
+type MemoryProcessedEventStore struct {
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+	mu       sync.Mutex
+}
+
+type processedEntry struct {
+	key         string
+	processedAt time.Time
+	pending     bool
+	// done is closed once a pending attempt finishes
+	done chan struct{}
+}
+
+// NewMemoryProcessedEventStore creates a MemoryProcessedEventStore. Entries
+// older than ttl are treated as unprocessed; a ttl of zero keeps them until
+// they are evicted.
+func NewMemoryProcessedEventStore(capacity int, ttl time.Duration) *MemoryProcessedEventStore {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+
+	return &MemoryProcessedEventStore{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Process runs fn unless the event was already processed. A duplicate that
+// arrives while the event is being processed waits for that attempt and
+// runs fn itself if the attempt fails, since a failed fn releases the event
+// for a retry.
+func (s *MemoryProcessedEventStore) Process(ctx context.Context, listener, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	key := listener + "\x00" + eventID
+
+	elem, processed, err := s.claim(ctx, key)
+	if elem == nil {
+		return processed, err
+	}
+	entry := elem.Value.(*processedEntry)
+
+	err = fn(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer close(entry.done)
+
+	if current, ok := s.entries[key]; ok && current == elem {
+		if err != nil {
+			s.remove(elem)
+		} else {
+			entry := elem.Value.(*processedEntry)
+			entry.pending = false
+			entry.processedAt = time.Now()
+		}
+	}
+
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// claim records key as pending and returns its element. It returns no
+// element when the event was already processed or ctx is done while waiting
+// for a concurrent attempt.
+func (s *MemoryProcessedEventStore) claim(ctx context.Context, key string) (*list.Element, bool, error) {
+	s.mu.Lock()
+	for {
+		elem, ok := s.entries[key]
+		if !ok {
+			break
+		}
+
+		entry := elem.Value.(*processedEntry)
+		if entry.pending {
+			s.mu.Unlock()
+			select {
+			case <-entry.done:
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			}
+			s.mu.Lock()
+			continue
+		}
+		if s.ttl <= 0 || time.Since(entry.processedAt) < s.ttl {
+			s.order.MoveToFront(elem)
+			s.mu.Unlock()
+			return nil, false, nil
+		}
+		s.remove(elem)
+		break
+	}
+	defer s.mu.Unlock()
+
+	elem := s.order.PushFront(&processedEntry{key: key, processedAt: time.Now(), pending: true, done: make(chan struct{})})
+	s.entries[key] = elem
+	for s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+	return elem, false, nil
+}
+
+func (s *MemoryProcessedEventStore) DeleteBefore(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for elem := s.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		entry := elem.Value.(*processedEntry)
+		if !entry.pending && entry.processedAt.Before(before) {
+			s.remove(elem)
+		}
+		elem = prev
+	}
+	return nil
+}
+
+// Len returns the number of tracked events
+func (s *MemoryProcessedEventStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryProcessedEventStore) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.entries, elem.Value.(*processedEntry).key)
+}
@@ -0,0 +1,364 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sideEffect is a row written by a listener, standing in for its real work
+type sideEffect struct {
+	ID      uint `gorm:"primaryKey"`
+	EventID string
+}
+
+func newTestGormStore(t *testing.T) (*GormProcessedEventStore, *gorm.DB) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "events.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Error opening database: %v", err)
+	}
+	if err := db.AutoMigrate(&sideEffect{}); err != nil {
+		t.Fatalf("Error migrating: %v", err)
+	}
+
+	store, err := NewGormProcessedEventStore(db)
+	if err != nil {
+		t.Fatalf("Error creating store: %v", err)
+	}
+	return store, db
+}
+
+// recordSideEffect writes a row in the transaction Process hands to fn
+func recordSideEffect(eventID string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		tx, ok := TxFromContext(ctx)
+		if !ok {
+			return errors.New("no transaction in context")
+		}
+		return tx.Create(&sideEffect{EventID: eventID}).Error
+	}
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		t.Fatalf("Error counting rows: %v", err)
+	}
+	return count
+}
+
+func TestIdempotentListenerSkipsDuplicates(t *testing.T) {
+	store := NewMemoryProcessedEventStore(100, 0)
+
+	calls := 0
+	listener := NewIdempotentListener("mailer", ListenerFunc(func(ctx context.Context, event Event) error {
+		calls++
+		return nil
+	}), store)
+
+	event := NewBaseEvent("user.created")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := listener.Handle(ctx, event); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected listener to run once, got %d", calls)
+	}
+}
+
+func TestIdempotentListenerIsScopedPerListener(t *testing.T) {
+	store := NewMemoryProcessedEventStore(100, 0)
+	event := NewBaseEvent("user.created")
+	ctx := context.Background()
+
+	calls := 0
+	handler := ListenerFunc(func(ctx context.Context, event Event) error {
+		calls++
+		return nil
+	})
+
+	NewIdempotentListener("mailer", handler, store).Handle(ctx, event)
+	NewIdempotentListener("indexer", handler, store).Handle(ctx, event)
+
+	if calls != 2 {
+		t.Errorf("Expected each listener to handle the event, got %d calls", calls)
+	}
+}
+
+func TestIdempotentListenerRetriesAfterFailure(t *testing.T) {
+	store := NewMemoryProcessedEventStore(100, 0)
+	event := NewBaseEvent("user.created")
+	ctx := context.Background()
+
+	calls := 0
+	listener := NewIdempotentListener("mailer", ListenerFunc(func(ctx context.Context, event Event) error {
+		calls++
+		if calls == 1 {
+			return errors.New("smtp unavailable")
+		}
+		return nil
+	}), store)
+
+	if err := listener.Handle(ctx, event); err == nil {
+		t.Fatal("Expected first attempt to fail")
+	}
+	if err := listener.Handle(ctx, event); err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	listener.Handle(ctx, event)
+
+	if calls != 2 {
+		t.Errorf("Expected a failed event to be retried once, got %d calls", calls)
+	}
+}
+
+func TestMemoryProcessedEventStoreDuplicateDuringFailingAttempt(t *testing.T) {
+	store := NewMemoryProcessedEventStore(100, 0)
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		_, err := store.Process(ctx, "l", "1", func(ctx context.Context) error {
+			close(started)
+			<-release
+			return errors.New("smtp unavailable")
+		})
+		first <- err
+	}()
+	<-started
+
+	duplicate := make(chan bool, 1)
+	go func() {
+		processed, _ := store.Process(ctx, "l", "1", func(ctx context.Context) error { return nil })
+		duplicate <- processed
+	}()
+
+	// The duplicate waits for the pending attempt instead of skipping it
+	select {
+	case <-duplicate:
+		t.Fatal("Expected the duplicate to wait for the pending attempt")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-first; err == nil {
+		t.Fatal("Expected the first attempt to fail")
+	}
+	select {
+	case processed := <-duplicate:
+		if !processed {
+			t.Error("Expected the duplicate to process the event after the failed attempt")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the duplicate to finish")
+	}
+}
+
+func TestMemoryProcessedEventStoreDuplicateAfterPendingSuccess(t *testing.T) {
+	store := NewMemoryProcessedEventStore(100, 0)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go store.Process(ctx, "l", "1", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	duplicate := make(chan bool, 1)
+	go func() {
+		processed, _ := store.Process(ctx, "l", "1", func(ctx context.Context) error { return nil })
+		duplicate <- processed
+	}()
+	close(release)
+
+	if processed := <-duplicate; processed {
+		t.Error("Expected the duplicate to be skipped once the pending attempt succeeded")
+	}
+}
+
+func TestMemoryProcessedEventStoreEviction(t *testing.T) {
+	store := NewMemoryProcessedEventStore(2, 0)
+	ctx := context.Background()
+	noop := func(ctx context.Context) error { return nil }
+
+	store.Process(ctx, "l", "1", noop)
+	store.Process(ctx, "l", "2", noop)
+	store.Process(ctx, "l", "3", noop)
+
+	if store.Len() != 2 {
+		t.Errorf("Expected store to hold 2 entries, got %d", store.Len())
+	}
+	if processed, _ := store.Process(ctx, "l", "1", noop); !processed {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+}
+
+func TestMemoryProcessedEventStoreTTL(t *testing.T) {
+	store := NewMemoryProcessedEventStore(100, 50*time.Millisecond)
+	ctx := context.Background()
+	noop := func(ctx context.Context) error { return nil }
+
+	store.Process(ctx, "l", "1", noop)
+	if processed, _ := store.Process(ctx, "l", "1", noop); processed {
+		t.Error("Expected duplicate within TTL to be skipped")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if processed, _ := store.Process(ctx, "l", "1", noop); !processed {
+		t.Error("Expected entry to expire after TTL")
+	}
+
+	store.DeleteBefore(ctx, time.Now().Add(time.Second))
+	if store.Len() != 0 {
+		t.Errorf("Expected cleanup to remove all entries, got %d", store.Len())
+	}
+}
+
+func TestGormProcessedEventStoreSkipsDuplicates(t *testing.T) {
+	store, db := newTestGormStore(t)
+	ctx := context.Background()
+
+	for i, want := range []bool{true, false} {
+		processed, err := store.Process(ctx, "mailer", "event-1", recordSideEffect("event-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if processed != want {
+			t.Errorf("Attempt %d: expected processed=%v, got %v", i, want, processed)
+		}
+	}
+
+	if processed, _ := store.Process(ctx, "audit", "event-1", recordSideEffect("event-1")); !processed {
+		t.Error("Expected another listener to process the same event")
+	}
+	if got := countRows(t, db, &sideEffect{}); got != 2 {
+		t.Errorf("Expected one side effect per listener, got %d", got)
+	}
+}
+
+func TestGormProcessedEventStoreRollsBackOnError(t *testing.T) {
+	store, db := newTestGormStore(t)
+	ctx := context.Background()
+	failure := errors.New("smtp down")
+
+	processed, err := store.Process(ctx, "mailer", "event-1", func(ctx context.Context) error {
+		if err := recordSideEffect("event-1")(ctx); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) || processed {
+		t.Fatalf("Expected the listener error, got processed=%v err=%v", processed, err)
+	}
+	if got := countRows(t, db, &ProcessedEvent{}); got != 0 {
+		t.Errorf("Expected the record to be rolled back, got %d rows", got)
+	}
+	if got := countRows(t, db, &sideEffect{}); got != 0 {
+		t.Errorf("Expected the side effect to be rolled back, got %d rows", got)
+	}
+
+	if processed, err := store.Process(ctx, "mailer", "event-1", recordSideEffect("event-1")); err != nil || !processed {
+		t.Errorf("Expected a retry to process the event, got processed=%v err=%v", processed, err)
+	}
+}
+
+func TestGormProcessedEventStoreJoinsOuterTransaction(t *testing.T) {
+	store, db := newTestGormStore(t)
+	rollback := errors.New("rollback")
+
+	// The outer transaction fails after Process succeeded, taking the
+	// record and the side effect with it
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ctx := WithTx(context.Background(), tx)
+		if _, err := store.Process(ctx, "mailer", "event-1", recordSideEffect("event-1")); err != nil {
+			return err
+		}
+		if got := countRows(t, tx, &ProcessedEvent{}); got != 1 {
+			t.Errorf("Expected the record to be visible inside the transaction, got %d", got)
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("Expected the outer transaction to fail, got %v", err)
+	}
+	if got := countRows(t, db, &ProcessedEvent{}); got != 0 {
+		t.Errorf("Expected the record to be rolled back with the outer transaction, got %d", got)
+	}
+	if got := countRows(t, db, &sideEffect{}); got != 0 {
+		t.Errorf("Expected the side effect to be rolled back with the outer transaction, got %d", got)
+	}
+
+	// A failing listener only rolls back its savepoint
+	err = db.Transaction(func(tx *gorm.DB) error {
+		ctx := WithTx(context.Background(), tx)
+		if err := tx.Create(&sideEffect{EventID: "outer"}).Error; err != nil {
+			return err
+		}
+		if _, err := store.Process(ctx, "mailer", "event-2", func(ctx context.Context) error { return rollback }); !errors.Is(err, rollback) {
+			t.Errorf("Expected the listener error, got %v", err)
+		}
+		_, err := store.Process(ctx, "audit", "event-2", recordSideEffect("event-2"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := countRows(t, db, &ProcessedEvent{}); got != 1 {
+		t.Errorf("Expected only the successful record to be committed, got %d", got)
+	}
+	if got := countRows(t, db, &sideEffect{}); got != 2 {
+		t.Errorf("Expected the outer write and the successful side effect, got %d", got)
+	}
+}
+
+func TestGormProcessedEventStoreDeleteBefore(t *testing.T) {
+	store, db := newTestGormStore(t)
+	ctx := context.Background()
+	noop := func(ctx context.Context) error { return nil }
+
+	store.Process(ctx, "mailer", "event-1", noop)
+	if err := store.DeleteBefore(ctx, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := countRows(t, db, &ProcessedEvent{}); got != 0 {
+		t.Errorf("Expected old records to be removed, got %d", got)
+	}
+}
+
+type failingProcessedEventStore struct {
+	ProcessedEventStore
+}
+
+func (failingProcessedEventStore) DeleteBefore(ctx context.Context, before time.Time) error {
+	return errors.New("database is locked")
+}
+
+func TestProcessedEventCleanupLogsFailures(t *testing.T) {
+	logger := &recordingLogger{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	StartProcessedEventCleanup(ctx, failingProcessedEventStore{}, time.Millisecond, time.Hour, logger)
+
+	if !waitFor(t, func() bool { _, errs := logger.counts(); return errs > 0 }) {
+		t.Error("Expected the cleanup failure to be logged")
+	}
+}
@@ -0,0 +1,144 @@
+package events
+
+import "sort"
+
+// SubscriberKind describes how a subscriber receives events
+type SubscriberKind string
+
+const (
+	SubscriberSync    SubscriberKind = "sync"
+	SubscriberAsync   SubscriberKind = "async"
+	SubscriberOnce    SubscriberKind = "once"
+	SubscriberBatch   SubscriberKind = "batch"
+	SubscriberChannel SubscriberKind = "channel"
+	SubscriberGroup   SubscriberKind = "group"
+)
+
+// BusInfo is a point-in-time view of an event bus
+type BusInfo struct {
+	Topics   []TopicInfo `json:"topics"`
+	InFlight int64       `json:"in_flight"`
+	Metrics  Metrics     `json:"metrics"`
+}
+
+// TopicInfo describes a topic with subscribers or retained events
+type TopicInfo struct {
+	Topic       string           `json:"topic"`
+	Retained    int              `json:"retained"`
+	Subscribers []SubscriberInfo `json:"subscribers"`
+}
+
+// SubscriberInfo describes a single subscriber. Buffered and Capacity are
+// the channel occupancy for channel subscribers and the pending batch for
+// batch subscribers; groups report the totals of their members.
+type SubscriberInfo struct {
+	ID       string         `json:"id,omitempty"`
+	Kind     SubscriberKind `json:"kind"`
+	Group    string         `json:"group,omitempty"`
+	Filtered bool           `json:"filtered"`
+	InFlight int64          `json:"in_flight"`
+	Buffered int            `json:"buffered"`
+	Capacity int            `json:"capacity"`
+	SubscriptionStats
+	Members []SubscriberInfo `json:"members,omitempty"`
+}
+
+// Inspect returns the topics of the bus with their subscribers, sorted by topic
+func (bus *eventBus) Inspect() BusInfo {
+	bus.mu.RLock()
+	handlers := make(map[string][]*eventHandler, len(bus.handlers))
+	for topic, hs := range bus.handlers {
+		handlers[topic] = append([]*eventHandler(nil), hs...)
+	}
+	bus.mu.RUnlock()
+
+	retained := bus.retained.counts()
+
+	topics := make([]string, 0, len(handlers))
+	for topic := range handlers {
+		topics = append(topics, topic)
+	}
+	for topic := range retained {
+		if _, ok := handlers[topic]; !ok {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+
+	info := BusInfo{
+		Topics:  make([]TopicInfo, 0, len(topics)),
+		Metrics: bus.Metrics(),
+	}
+	for _, topic := range topics {
+		topicInfo := TopicInfo{
+			Topic:       topic,
+			Retained:    retained[topic],
+			Subscribers: make([]SubscriberInfo, 0, len(handlers[topic])),
+		}
+		for _, handler := range handlers[topic] {
+			subscriber := handler.describe()
+			info.InFlight += subscriber.InFlight
+			topicInfo.Subscribers = append(topicInfo.Subscribers, subscriber)
+		}
+		info.Topics = append(info.Topics, topicInfo)
+	}
+
+	return info
+}
+
+func (h *eventHandler) describe() SubscriberInfo {
+	info := SubscriberInfo{
+		ID:                h.id,
+		Filtered:          h.filter != nil,
+		InFlight:          h.inFlight.Load(),
+		SubscriptionStats: h.stats.snapshot(),
+	}
+
+	switch {
+	case h.sink != nil:
+		h.sink.describe(&info)
+	case h.batcher != nil:
+		info.Kind = SubscriberBatch
+		info.Buffered, info.Capacity = h.batcher.occupancy()
+	case h.once:
+		info.Kind = SubscriberOnce
+	case h.async:
+		info.Kind = SubscriberAsync
+	default:
+		info.Kind = SubscriberSync
+	}
+	return info
+}
+
+func (cs *ChannelSubscriber) describe(info *SubscriberInfo) {
+	info.ID = cs.id
+	info.Kind = SubscriberChannel
+	info.Buffered = len(cs.channel)
+	info.Capacity = cap(cs.channel)
+	info.SubscriptionStats = cs.stats.snapshot()
+}
+
+func (g *consumerGroup) describe(info *SubscriberInfo) {
+	g.mu.Lock()
+	members := append([]*ChannelSubscriber(nil), g.members...)
+	g.mu.Unlock()
+
+	info.Kind = SubscriberGroup
+	info.Group = g.name
+	for _, member := range members {
+		var m SubscriberInfo
+		member.describe(&m)
+		info.Buffered += m.Buffered
+		info.Capacity += m.Capacity
+		info.Delivered += m.Delivered
+		info.Dropped += m.Dropped
+		info.Members = append(info.Members, m)
+	}
+}
+
+// occupancy returns the number of pending events and the batch size
+func (b *batcher) occupancy() (int, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending), b.maxSize
+}
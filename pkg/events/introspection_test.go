@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInspect(t *testing.T) {
+	bus := NewChannelEventBus(nil)
+	defer bus.Close()
+	bus.RetainTopic("config.changed", 5)
+
+	bus.Subscribe("user.created", func(event Event) {})
+	bus.SubscribeAsync("user.created", func(event Event) {}, false)
+	bus.SubscribeOnce("user.created", func(event Event) {})
+	bus.SubscribeFiltered("user.created", func(event Event) bool { return true }, func(event Event) {})
+	bus.SubscribeBatch("user.created", 10, time.Hour, func(ctx context.Context, events []Event) error { return nil })
+	channel := bus.SubscribeChannel("user.created", 1)
+	bus.SubscribeChannelGroup("user.created", "mailer", 4)
+	bus.SubscribeChannelGroup("user.created", "mailer", 4)
+
+	bus.PublishEvent(context.Background(), NewBaseEvent("user.created"))
+	bus.PublishEvent(context.Background(), NewBaseEvent("user.created"))
+	bus.Publish("config.changed", NewBaseEvent("config.changed"))
+	bus.WaitAsync()
+
+	info := bus.Inspect()
+	if len(info.Topics) != 2 || info.Topics[0].Topic != "config.changed" || info.Topics[1].Topic != "user.created" {
+		t.Fatalf("Unexpected topics: %+v", info.Topics)
+	}
+	if info.Topics[0].Retained != 1 || len(info.Topics[0].Subscribers) != 0 {
+		t.Errorf("Unexpected retained topic: %+v", info.Topics[0])
+	}
+
+	kinds := make(map[SubscriberKind]SubscriberInfo)
+	for _, subscriber := range info.Topics[1].Subscribers {
+		if subscriber.Filtered {
+			kinds["filtered"] = subscriber
+			continue
+		}
+		kinds[subscriber.Kind] = subscriber
+	}
+
+	// The once handler removed itself after the first event
+	if len(kinds) != 6 {
+		t.Fatalf("Expected sync, async, filtered, batch, channel and group subscribers, got %+v", kinds)
+	}
+	if got := kinds[SubscriberChannel]; got.ID != channel.ID() || got.Buffered != 1 || got.Capacity != 1 || got.Dropped != 1 {
+		t.Errorf("Unexpected channel subscriber: %+v", got)
+	}
+	if got := kinds[SubscriberGroup]; got.Group != "mailer" || len(got.Members) != 2 || got.Buffered != 2 || got.Capacity != 8 {
+		t.Errorf("Unexpected group subscriber: %+v", got)
+	}
+	if got := kinds[SubscriberBatch]; got.Buffered != 2 || got.Capacity != 10 {
+		t.Errorf("Unexpected batch subscriber: %+v", got)
+	}
+	if got := kinds[SubscriberSync]; got.Delivered != 2 || got.InFlight != 0 {
+		t.Errorf("Unexpected sync subscriber: %+v", got)
+	}
+	if info.Metrics.Published != 3 {
+		t.Errorf("Expected 3 published events, got %+v", info.Metrics)
+	}
+}
+
+func TestInspectInFlight(t *testing.T) {
+	bus := NewEventBus(nil)
+	defer bus.Close()
+
+	release := make(chan struct{})
+	bus.SubscribeAsync("slow.task", func(event Event) {
+		<-release
+	}, false)
+
+	bus.Publish("slow.task", NewBaseEvent("slow.task"))
+	bus.Publish("slow.task", NewBaseEvent("slow.task"))
+
+	if info := bus.Inspect(); info.InFlight != 2 || info.Topics[0].Subscribers[0].InFlight != 2 {
+		t.Errorf("Expected 2 in-flight deliveries, got %+v", info)
+	}
+
+	close(release)
+	bus.WaitAsync()
+
+	if info := bus.Inspect(); info.InFlight != 0 {
+		t.Errorf("Expected no in-flight deliveries, got %d", info.InFlight)
+	}
+}
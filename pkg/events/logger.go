@@ -0,0 +1,70 @@
+package events
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Logger is the part of the application logger used to report failures the
+// bus cannot return to a caller, such as handlers that time out or run slow.
+// *logger.Logger satisfies it.
+type Logger interface {
+	LogWarn(ctx context.Context, message string, fields ...map[string]interface{})
+	LogError(ctx context.Context, message string, err error, fields ...map[string]interface{})
+}
+
+// zerologLogger writes to the global zerolog logger. It stands in for an
+// unset EventBusConfig.Logger.
+type zerologLogger struct{}
+
+func (zerologLogger) LogWarn(ctx context.Context, message string, fields ...map[string]interface{}) {
+	event := log.Warn()
+	for _, f := range fields {
+		event = event.Fields(f)
+	}
+	event.Msg(message)
+}
+
+func (zerologLogger) LogError(ctx context.Context, message string, err error, fields ...map[string]interface{}) {
+	event := log.Error().Err(err)
+	for _, f := range fields {
+		event = event.Fields(f)
+	}
+	event.Msg(message)
+}
+
+// loggerOrDefault returns logger, or the zerolog logger when it is nil
+func loggerOrDefault(logger Logger) Logger {
+	if logger == nil {
+		return zerologLogger{}
+	}
+	return logger
+}
+
+// loggerProvider is implemented by the buses of this package, so that
+// components attached to a bus report through the bus logger
+type loggerProvider interface {
+	busLogger() Logger
+}
+
+func (bus *eventBus) busLogger() Logger {
+	return bus.logger
+}
+
+func (ceb *ChannelEventBus) busLogger() Logger {
+	return ceb.bus.logger
+}
+
+func (bus *NATSEventBus) busLogger() Logger {
+	return bus.logger
+}
+
+// busLogger returns the logger of bus, or the zerolog logger for buses
+// implemented elsewhere
+func busLogger(bus EventBus) Logger {
+	if p, ok := bus.(loggerProvider); ok {
+		return p.busLogger()
+	}
+	return zerologLogger{}
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metadata carries who triggered an event and how it correlates with the
+// request that produced it
+type Metadata struct {
+	ActorID       string `json:"actor_id,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	CausationID   string `json:"causation_id,omitempty"`
+}
+
+type metadataKey int
+
+const (
+	actorIDKey metadataKey = iota
+	correlationIDKey
+	causationIDKey
+)
+
+// WithActorID stores the ID of the actor performing the current operation
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorIDKey, actorID)
+}
+
+// WithCorrelationID stores the correlation ID shared by every event of a request
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, correlationID)
+}
+
+// WithCausationID stores the ID of the event that caused the current operation
+func WithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, causationIDKey, causationID)
+}
+
+// MetadataFromContext builds event metadata from the values stored in ctx.
+// When no correlation ID was stored, the active trace ID is used instead.
+func MetadataFromContext(ctx context.Context) Metadata {
+	var metadata Metadata
+	if ctx == nil {
+		return metadata
+	}
+
+	if actorID, ok := ctx.Value(actorIDKey).(string); ok {
+		metadata.ActorID = actorID
+	}
+	if correlationID, ok := ctx.Value(correlationIDKey).(string); ok {
+		metadata.CorrelationID = correlationID
+	}
+	if causationID, ok := ctx.Value(causationIDKey).(string); ok {
+		metadata.CausationID = causationID
+	}
+
+	if metadata.CorrelationID == "" {
+		if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+			metadata.CorrelationID = spanCtx.TraceID().String()
+		}
+	}
+
+	return metadata
+}
@@ -0,0 +1,55 @@
+package events
+
+import "sync/atomic"
+
+// Metrics is a snapshot of the counters kept by an event bus
+type Metrics struct {
+	Published         uint64 `json:"published"`
+	Delivered         uint64 `json:"delivered"`
+	Dropped           uint64 `json:"dropped"`
+	Errors            uint64 `json:"errors"`
+	FilterEvaluations uint64 `json:"filter_evaluations"`
+	FilterRejections  uint64 `json:"filter_rejections"`
+	// Abandoned counts handlers still running after missing their deadline
+	Abandoned int64 `json:"abandoned"`
+}
+
+type busMetrics struct {
+	published         atomic.Uint64
+	delivered         atomic.Uint64
+	dropped           atomic.Uint64
+	errors            atomic.Uint64
+	filterEvaluations atomic.Uint64
+	filterRejections  atomic.Uint64
+	abandoned         atomic.Int64
+}
+
+func (m *busMetrics) snapshot() Metrics {
+	return Metrics{
+		Published:         m.published.Load(),
+		Delivered:         m.delivered.Load(),
+		Dropped:           m.dropped.Load(),
+		Errors:            m.errors.Load(),
+		FilterEvaluations: m.filterEvaluations.Load(),
+		FilterRejections:  m.filterRejections.Load(),
+		Abandoned:         m.abandoned.Load(),
+	}
+}
+
+// EventPredicate decides whether an event is delivered to a filtered subscriber
+type EventPredicate func(event Event) bool
+
+// evaluate runs predicate against event and records the evaluation.
+// A nil predicate accepts every event without being counted.
+func (m *busMetrics) evaluate(predicate EventPredicate, event Event) bool {
+	if predicate == nil {
+		return true
+	}
+
+	m.filterEvaluations.Add(1)
+	if event == nil || !predicate(event) {
+		m.filterRejections.Add(1)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,452 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSConfig configures the NATS-backed event bus
+type NATSConfig struct {
+	URL           string
+	Name          string
+	SubjectPrefix string
+	QueueGroup    string
+	JetStream     bool
+	Stream        string
+	Durable       string
+	AckWait       time.Duration
+	ReconnectWait time.Duration
+	MaxReconnects int
+}
+
+// NATSEventBus distributes events between processes through NATS subjects.
+// Handlers are kept in a local EventBus; every topic with at least one
+// handler holds a NATS subscription that feeds incoming events into it.
+type NATSEventBus struct {
+	config NATSConfig
+	conn   *nats.Conn
+	js     nats.JetStreamContext
+	local  EventBus
+	codec  *eventCodec
+	logger Logger
+	subs   map[string]*nats.Subscription
+	topics map[string]string
+	mu     sync.RWMutex
+
+	// closed is closed once the connection is closed, after a drain
+	closed chan struct{}
+}
+
+// DefaultNATSConfig returns a NATSConfig pointing at a local NATS server
+func DefaultNATSConfig() NATSConfig {
+	return NATSConfig{
+		URL:           nats.DefaultURL,
+		Name:          "boilerplate-go",
+		SubjectPrefix: "events",
+		Stream:        "EVENTS",
+		AckWait:       30 * time.Second,
+		ReconnectWait: 2 * time.Second,
+		MaxReconnects: -1,
+	}
+}
+
+// NewNATSEventBus connects to NATS and returns an EventBus backed by it
+func NewNATSEventBus(cfg NATSConfig, config *EventBusConfig) (*NATSEventBus, error) {
+	defaults := DefaultNATSConfig()
+	if cfg.URL == "" {
+		cfg.URL = defaults.URL
+	}
+	if cfg.Name == "" {
+		cfg.Name = defaults.Name
+	}
+	if cfg.Stream == "" {
+		cfg.Stream = defaults.Stream
+	}
+	if cfg.AckWait <= 0 {
+		cfg.AckWait = defaults.AckWait
+	}
+	if cfg.ReconnectWait <= 0 {
+		cfg.ReconnectWait = defaults.ReconnectWait
+	}
+	if cfg.MaxReconnects == 0 {
+		cfg.MaxReconnects = defaults.MaxReconnects
+	}
+
+	var logger Logger
+	if config != nil {
+		logger = config.Logger
+	}
+
+	bus := &NATSEventBus{
+		config: cfg,
+		local:  NewEventBus(config),
+		codec:  newEventCodec(),
+		logger: loggerOrDefault(logger),
+		subs:   make(map[string]*nats.Subscription),
+		topics: make(map[string]string),
+		closed: make(chan struct{}),
+	}
+
+	conn, err := nats.Connect(cfg.URL,
+		nats.Name(cfg.Name),
+		nats.ReconnectWait(cfg.ReconnectWait),
+		nats.MaxReconnects(cfg.MaxReconnects),
+		nats.ClosedHandler(func(*nats.Conn) { close(bus.closed) }),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", cfg.URL, err)
+	}
+	bus.conn = conn
+
+	if cfg.JetStream {
+		if err := bus.setupJetStream(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return bus, nil
+}
+
+func (bus *NATSEventBus) setupJetStream() error {
+	js, err := bus.conn.JetStream(nats.PublishAsyncErrHandler(func(_ nats.JetStream, msg *nats.Msg, err error) {
+		bus.publishFailed(msg.Subject, err)
+	}))
+	if err != nil {
+		return fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	if _, err := js.StreamInfo(bus.config.Stream); err != nil {
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			return fmt.Errorf("failed to look up stream %s: %w", bus.config.Stream, err)
+		}
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:     bus.config.Stream,
+			Subjects: []string{bus.subject("") + ">"},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stream %s: %w", bus.config.Stream, err)
+		}
+	}
+
+	bus.js = js
+	return nil
+}
+
+// RegisterEventType registers the concrete type that events of a topic are decoded into
+func (bus *NATSEventBus) RegisterEventType(topic string, factory EventFactory) {
+	bus.codec.register(topic, factory)
+}
+
+func (bus *NATSEventBus) Subscribe(topic string, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	return bus.track(bus.local.Subscribe(topic, fn, opts...))
+}
+
+func (bus *NATSEventBus) SubscribeOnce(topic string, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	return bus.track(bus.local.SubscribeOnce(topic, fn, opts...))
+}
+
+func (bus *NATSEventBus) SubscribeAsync(topic string, fn interface{}, transactional bool, opts ...SubscribeOption) (Subscription, error) {
+	return bus.track(bus.local.SubscribeAsync(topic, fn, transactional, opts...))
+}
+
+func (bus *NATSEventBus) SubscribeOnceAsync(topic string, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	return bus.track(bus.local.SubscribeOnceAsync(topic, fn, opts...))
+}
+
+func (bus *NATSEventBus) SubscribeFiltered(topic string, predicate EventPredicate, fn interface{}, opts ...SubscribeOption) (Subscription, error) {
+	return bus.track(bus.local.SubscribeFiltered(topic, predicate, fn, opts...))
+}
+
+func (bus *NATSEventBus) SubscribeBatch(topic string, maxSize int, maxWait time.Duration, handler BatchHandler, opts ...BatchOption) (Subscription, error) {
+	return bus.track(bus.local.SubscribeBatch(topic, maxSize, maxWait, handler, opts...))
+}
+
+// track makes sure the topic of a new local subscription is fed from NATS
+// and wraps the subscription so that unsubscribing releases the subject
+func (bus *NATSEventBus) track(sub Subscription, err error) (Subscription, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bus.ensureSubscription(sub.Topic()); err != nil {
+		sub.Unsubscribe()
+		return nil, err
+	}
+
+	bus.mu.Lock()
+	bus.topics[sub.ID()] = sub.Topic()
+	bus.mu.Unlock()
+
+	return &natsSubscription{Subscription: sub, bus: bus}, nil
+}
+
+// Unsubscribe removes a subscription and drops the NATS subscription of its
+// topic once no local handler is left
+func (bus *NATSEventBus) Unsubscribe(id string) error {
+	if err := bus.local.Unsubscribe(id); err != nil {
+		return err
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	topic, ok := bus.topics[id]
+	delete(bus.topics, id)
+	if !ok || bus.local.HasCallback(topic) {
+		return nil
+	}
+
+	if sub, ok := bus.subs[topic]; ok {
+		delete(bus.subs, topic)
+		return sub.Unsubscribe()
+	}
+	return nil
+}
+
+// UnsubscribeHandler removes the first subscription of fn on topic.
+//
+// Deprecated: use Unsubscribe with the ID of the Subscription returned by
+// Subscribe, or Subscription.Unsubscribe.
+func (bus *NATSEventBus) UnsubscribeHandler(topic string, fn interface{}) error {
+	id, err := bus.local.(*eventBus).handlerID(topic, fn)
+	if err != nil {
+		return err
+	}
+	return bus.Unsubscribe(id)
+}
+
+type natsSubscription struct {
+	Subscription
+	bus *NATSEventBus
+}
+
+func (s *natsSubscription) Unsubscribe() error {
+	return s.bus.Unsubscribe(s.ID())
+}
+
+// Publish sends the event to the subject mapped from topic. Local handlers
+// receive it through their NATS subscription like any other instance.
+func (bus *NATSEventBus) Publish(topic string, args ...interface{}) error {
+	data, err := bus.encode(topic, args...)
+	if err != nil {
+		return err
+	}
+
+	if bus.js != nil {
+		if _, err := bus.js.Publish(bus.subject(topic), data); err != nil {
+			return fmt.Errorf("failed to publish to JetStream: %w", err)
+		}
+		return nil
+	}
+
+	if err := bus.conn.Publish(bus.subject(topic), data); err != nil {
+		return fmt.Errorf("failed to publish to NATS: %w", err)
+	}
+	return nil
+}
+
+// PublishAsync sends the event without waiting for JetStream to
+// acknowledge it. Failures, including rejected acknowledgements, are logged.
+func (bus *NATSEventBus) PublishAsync(topic string, args ...interface{}) {
+	data, err := bus.encode(topic, args...)
+	if err != nil {
+		bus.publishFailed(bus.subject(topic), err)
+		return
+	}
+
+	if bus.js != nil {
+		if _, err := bus.js.PublishAsync(bus.subject(topic), data); err != nil {
+			bus.publishFailed(bus.subject(topic), err)
+		}
+		return
+	}
+	if err := bus.conn.Publish(bus.subject(topic), data); err != nil {
+		bus.publishFailed(bus.subject(topic), err)
+	}
+}
+
+func (bus *NATSEventBus) publishFailed(subject string, err error) {
+	bus.logger.LogError(context.Background(), "Failed to publish event to NATS", err, map[string]interface{}{
+		"subject": subject,
+	})
+}
+
+func (bus *NATSEventBus) HasCallback(topic string) bool {
+	return bus.local.HasCallback(topic)
+}
+
+// WaitAsync flushes pending publishes and waits for async handlers
+func (bus *NATSEventBus) WaitAsync() {
+	if bus.js != nil {
+		<-bus.js.PublishAsyncComplete()
+	}
+	bus.conn.Flush()
+	bus.local.WaitAsync()
+}
+
+// RetainTopic retains events of topic as they arrive from NATS
+func (bus *NATSEventBus) RetainTopic(topic string, limit int) error {
+	return bus.local.RetainTopic(topic, limit)
+}
+
+func (bus *NATSEventBus) Last(topic string) (Event, bool) {
+	return bus.local.Last(topic)
+}
+
+func (bus *NATSEventBus) Metrics() Metrics {
+	return bus.local.Metrics()
+}
+
+// Inspect returns the local subscribers fed by NATS
+func (bus *NATSEventBus) Inspect() BusInfo {
+	return bus.local.Inspect()
+}
+
+// Close drains the connection, which lets in-flight messages reach the
+// local handlers and flushes pending publishes, and closes the local bus
+// once the drain has finished
+func (bus *NATSEventBus) Close() error {
+	bus.mu.Lock()
+	for topic := range bus.subs {
+		delete(bus.subs, topic)
+	}
+	bus.mu.Unlock()
+
+	if err := bus.conn.Drain(); err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+		bus.conn.Close()
+	}
+	<-bus.closed
+
+	return bus.local.Close()
+}
+
+func (bus *NATSEventBus) ensureSubscription(topic string) error {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if _, ok := bus.subs[topic]; ok {
+		return nil
+	}
+
+	var sub *nats.Subscription
+	var err error
+	subject := bus.subject(topic)
+	handler := bus.messageHandler(topic)
+
+	switch {
+	case bus.js != nil:
+		opts := []nats.SubOpt{
+			nats.BindStream(bus.config.Stream),
+			nats.ManualAck(),
+			nats.AckWait(bus.config.AckWait),
+		}
+		if bus.config.Durable != "" {
+			durable, err := bus.ensureDurableConsumer(topic, subject)
+			if err != nil {
+				return err
+			}
+			opts = append(opts, nats.Bind(bus.config.Stream, durable))
+		}
+		if bus.config.QueueGroup != "" {
+			sub, err = bus.js.QueueSubscribe(subject, bus.config.QueueGroup, handler, opts...)
+		} else {
+			sub, err = bus.js.Subscribe(subject, handler, opts...)
+		}
+	case bus.config.QueueGroup != "":
+		sub, err = bus.conn.QueueSubscribe(subject, bus.config.QueueGroup, handler)
+	default:
+		sub, err = bus.conn.Subscribe(subject, handler)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to subject %s: %w", subject, err)
+	}
+
+	bus.subs[topic] = sub
+	return nil
+}
+
+// ensureDurableConsumer creates the durable consumer of a topic unless it
+// already exists. Consumers created here are bound instead of being owned by
+// the subscription, so unsubscribing or restarting keeps their position.
+func (bus *NATSEventBus) ensureDurableConsumer(topic, subject string) (string, error) {
+	durable := durableName(bus.config.Durable, topic)
+
+	_, err := bus.js.ConsumerInfo(bus.config.Stream, durable)
+	if err == nil {
+		return durable, nil
+	}
+	if !errors.Is(err, nats.ErrConsumerNotFound) {
+		return "", fmt.Errorf("failed to look up consumer %s: %w", durable, err)
+	}
+
+	_, err = bus.js.AddConsumer(bus.config.Stream, &nats.ConsumerConfig{
+		Durable:        durable,
+		FilterSubject:  subject,
+		DeliverSubject: nats.NewInbox(),
+		DeliverGroup:   bus.config.QueueGroup,
+		AckPolicy:      nats.AckExplicitPolicy,
+		AckWait:        bus.config.AckWait,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create consumer %s: %w", durable, err)
+	}
+	return durable, nil
+}
+
+// messageHandler decodes messages received for topic and dispatches them
+// to the local handlers
+func (bus *NATSEventBus) messageHandler(topic string) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		event, err := bus.codec.decode(topic, msg.Data)
+		if err != nil {
+			// A payload that cannot be decoded will never succeed, so it
+			// is terminated instead of being redelivered forever
+			if bus.js != nil {
+				msg.Term()
+			}
+			return
+		}
+
+		if err := bus.local.Publish(topic, event); err != nil {
+			if bus.js != nil {
+				msg.Nak()
+			}
+			return
+		}
+
+		if bus.js != nil {
+			msg.Ack()
+		}
+	}
+}
+
+func (bus *NATSEventBus) encode(topic string, args ...interface{}) ([]byte, error) {
+	event, err := eventArg(args...)
+	if err != nil {
+		return nil, err
+	}
+	return bus.codec.encode(topic, event)
+}
+
+// subject maps a topic to its NATS subject
+func (bus *NATSEventBus) subject(topic string) string {
+	topic = subjectReplacer.Replace(topic)
+	if bus.config.SubjectPrefix == "" {
+		return topic
+	}
+	return bus.config.SubjectPrefix + "." + topic
+}
+
+var subjectReplacer = strings.NewReplacer(" ", "_", "\t", "_", "*", "_", ">", "_")
+
+// durableName builds a consumer name, which may not contain dots, for a topic
+func durableName(durable, topic string) string {
+	return durable + "_" + strings.NewReplacer(".", "_", ">", "_").Replace(topic)
+}
@@ -0,0 +1,365 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// runNATSServer starts an embedded NATS server with JetStream stored in a
+// temporary directory
+func runNATSServer(t *testing.T) *natsserver.Server {
+	server, err := natsserver.NewServer(&natsserver.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create NATS server: %v", err)
+	}
+
+	go server.Start()
+	if !server.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+	t.Cleanup(func() {
+		server.Shutdown()
+		server.WaitForShutdown()
+	})
+	return server
+}
+
+func newTestNATSEventBus(t *testing.T, cfg NATSConfig, config *EventBusConfig) *NATSEventBus {
+	if cfg.SubjectPrefix == "" {
+		cfg.SubjectPrefix = "events"
+	}
+	bus, err := NewNATSEventBus(cfg, config)
+	if err != nil {
+		t.Fatalf("Error creating NATS event bus: %v", err)
+	}
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func userCreated(id int) *UserCreatedEvent {
+	return &UserCreatedEvent{
+		BaseEvent: NewBaseEvent("user.created"),
+		UserID:    id,
+		Username:  "john_doe",
+	}
+}
+
+func receiveUser(t *testing.T, received <-chan *UserCreatedEvent) *UserCreatedEvent {
+	t.Helper()
+	select {
+	case event := <-received:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for event")
+		return nil
+	}
+}
+
+func TestNATSEventBusCrossInstance(t *testing.T) {
+	server := runNATSServer(t)
+	publisher := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL()}, nil)
+	subscriber := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL()}, nil)
+	subscriber.RegisterEventType("user.created", func() Event { return &UserCreatedEvent{} })
+
+	received := make(chan *UserCreatedEvent, 1)
+	_, err := subscriber.Subscribe("user.created", func(event Event) {
+		received <- event.(*UserCreatedEvent)
+	})
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+	subscriber.WaitAsync()
+
+	event := userCreated(1)
+	if err := publisher.Publish("user.created", event); err != nil {
+		t.Fatalf("Error publishing event: %v", err)
+	}
+
+	got := receiveUser(t, received)
+	if got.UserID != 1 || got.Username != "john_doe" || got.GetID() != event.GetID() {
+		t.Errorf("Received event data mismatch: %+v", got)
+	}
+}
+
+func TestNATSEventBusUnregisteredType(t *testing.T) {
+	server := runNATSServer(t)
+	bus := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL()}, nil)
+
+	received := make(chan Event, 1)
+	bus.Subscribe("test.event", func(event Event) {
+		received <- event
+	})
+	bus.WaitAsync()
+
+	event := NewBaseEvent("test.event")
+	bus.Publish("test.event", event)
+
+	select {
+	case got := <-received:
+		remote, ok := got.(*RemoteEvent)
+		if !ok {
+			t.Fatalf("Expected *RemoteEvent, got %T", got)
+		}
+		if remote.GetID() != event.GetID() || len(remote.Payload) == 0 {
+			t.Errorf("Remote event mismatch: %+v", remote)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler didn't receive event")
+	}
+}
+
+func TestNATSEventBusQueueGroup(t *testing.T) {
+	server := runNATSServer(t)
+	publisher := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL()}, nil)
+
+	var mu sync.Mutex
+	counts := make(map[int]int)
+	var wg sync.WaitGroup
+	const total = 20
+	wg.Add(total)
+
+	for i := 0; i < 2; i++ {
+		worker := i
+		bus := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL(), QueueGroup: "workers"}, nil)
+		bus.Subscribe("job.created", func(event Event) {
+			mu.Lock()
+			counts[worker]++
+			mu.Unlock()
+			wg.Done()
+		})
+		bus.WaitAsync()
+	}
+
+	for i := 0; i < total; i++ {
+		publisher.Publish("job.created", NewBaseEvent("job.created"))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timeout waiting for queue group deliveries")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts[0]+counts[1] != total {
+		t.Errorf("Expected %d deliveries in total, got %v", total, counts)
+	}
+	if counts[0] == 0 || counts[1] == 0 {
+		t.Errorf("Expected work to be spread across the group, got %v", counts)
+	}
+}
+
+func TestNATSEventBusUnsubscribe(t *testing.T) {
+	server := runNATSServer(t)
+	bus := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL()}, nil)
+
+	baseline := server.NumSubscriptions()
+	var calls atomic.Int32
+	sub, _ := bus.Subscribe("test.event", func(event Event) {
+		calls.Add(1)
+	})
+	bus.WaitAsync()
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Error unsubscribing: %v", err)
+	}
+	bus.WaitAsync()
+
+	bus.Publish("test.event", NewBaseEvent("test.event"))
+	bus.WaitAsync()
+	time.Sleep(100 * time.Millisecond)
+
+	if got := calls.Load(); got != 0 {
+		t.Errorf("Expected no calls after unsubscribe, got %d", got)
+	}
+	if bus.HasCallback("test.event") {
+		t.Error("Should not have callback after unsubscribe")
+	}
+	if got := server.NumSubscriptions(); got != baseline {
+		t.Errorf("Expected the NATS subscription to be released, got %d subscriptions instead of %d", got, baseline)
+	}
+}
+
+func TestNATSEventBusCreatesStream(t *testing.T) {
+	server := runNATSServer(t)
+	bus := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL(), JetStream: true, Stream: "TEST"}, nil)
+
+	if err := bus.Publish("user.created", userCreated(1)); err != nil {
+		t.Fatalf("Error publishing event: %v", err)
+	}
+
+	info, err := bus.js.StreamInfo("TEST")
+	if err != nil {
+		t.Fatalf("Expected the stream to be created: %v", err)
+	}
+	if len(info.Config.Subjects) != 1 || info.Config.Subjects[0] != "events.>" {
+		t.Errorf("Expected the stream to capture the prefix, got %v", info.Config.Subjects)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("Expected the event to be stored, got %d messages", info.State.Msgs)
+	}
+
+	// A second instance reuses the existing stream
+	newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL(), JetStream: true, Stream: "TEST"}, nil)
+}
+
+func TestNATSEventBusJetStreamDeliveryIsAcked(t *testing.T) {
+	server := runNATSServer(t)
+	bus := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL(), JetStream: true, Durable: "svc"}, nil)
+	bus.RegisterEventType("user.created", func() Event { return &UserCreatedEvent{} })
+
+	received := make(chan *UserCreatedEvent, 1)
+	bus.Subscribe("user.created", func(event Event) {
+		received <- event.(*UserCreatedEvent)
+	})
+	bus.Publish("user.created", userCreated(7))
+
+	if got := receiveUser(t, received); got.UserID != 7 {
+		t.Errorf("Received event data mismatch: %+v", got)
+	}
+
+	durable := durableName("svc", "user.created")
+	acked := waitFor(t, func() bool {
+		info, err := bus.js.ConsumerInfo(bus.config.Stream, durable)
+		return err == nil && info.AckFloor.Consumer == 1 && info.NumAckPending == 0
+	})
+	if !acked {
+		t.Error("Expected the delivery to be acknowledged")
+	}
+}
+
+func TestNATSEventBusDurableConsumerResumes(t *testing.T) {
+	server := runNATSServer(t)
+	cfg := NATSConfig{URL: server.ClientURL(), SubjectPrefix: "events", JetStream: true, Durable: "svc"}
+	publisher := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL(), JetStream: true}, nil)
+
+	first, err := NewNATSEventBus(cfg, nil)
+	if err != nil {
+		t.Fatalf("Error creating NATS event bus: %v", err)
+	}
+	first.RegisterEventType("user.created", func() Event { return &UserCreatedEvent{} })
+	received := make(chan *UserCreatedEvent, 2)
+	first.Subscribe("user.created", func(event Event) {
+		received <- event.(*UserCreatedEvent)
+	})
+
+	publisher.Publish("user.created", userCreated(1))
+	if got := receiveUser(t, received); got.UserID != 1 {
+		t.Fatalf("Expected the first event, got %+v", got)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Error closing bus: %v", err)
+	}
+
+	// Published while no instance of the consumer is running
+	publisher.Publish("user.created", userCreated(2))
+
+	second := newTestNATSEventBus(t, cfg, nil)
+	second.RegisterEventType("user.created", func() Event { return &UserCreatedEvent{} })
+	_, err = second.Subscribe("user.created", func(event Event) {
+		received <- event.(*UserCreatedEvent)
+	})
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	if got := receiveUser(t, received); got.UserID != 2 {
+		t.Errorf("Expected the durable consumer to resume after the acked event, got %+v", got)
+	}
+	select {
+	case got := <-received:
+		t.Errorf("Expected no redelivery, got %+v", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestNATSEventBusPublishAsyncLogsFailures(t *testing.T) {
+	server := runNATSServer(t)
+	logger := &recordingLogger{}
+	bus := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL(), JetStream: true}, &EventBusConfig{Logger: logger})
+
+	// Without the stream nothing acknowledges the publish
+	if err := bus.js.DeleteStream(bus.config.Stream); err != nil {
+		t.Fatalf("Error deleting stream: %v", err)
+	}
+
+	bus.PublishAsync("user.created", userCreated(1))
+	bus.WaitAsync()
+
+	if _, errs := logger.counts(); errs != 1 {
+		t.Errorf("Expected the failed publish to be logged, got %d errors", errs)
+	}
+}
+
+func TestNATSEventBusCloseWaitsForDrain(t *testing.T) {
+	server := runNATSServer(t)
+	publisher := newTestNATSEventBus(t, NATSConfig{URL: server.ClientURL()}, nil)
+
+	subscriber, err := NewNATSEventBus(NATSConfig{URL: server.ClientURL(), SubjectPrefix: "events"}, nil)
+	if err != nil {
+		t.Fatalf("Error creating NATS event bus: %v", err)
+	}
+
+	started := make(chan struct{}, 1)
+	var handled atomic.Int32
+	subscriber.Subscribe("job.created", func(event Event) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		time.Sleep(20 * time.Millisecond)
+		handled.Add(1)
+	})
+	subscriber.WaitAsync()
+
+	const total = 5
+	for i := 0; i < total; i++ {
+		publisher.Publish("job.created", NewBaseEvent("job.created"))
+	}
+	publisher.WaitAsync()
+	<-started
+
+	if err := subscriber.Close(); err != nil {
+		t.Fatalf("Error closing bus: %v", err)
+	}
+	if got := handled.Load(); got != total {
+		t.Errorf("Expected Close to wait for the %d received events, got %d", total, got)
+	}
+	if status := subscriber.conn.Status(); status != nats.CLOSED {
+		t.Errorf("Expected the connection to be closed, got %v", status)
+	}
+}
+
+func TestNATSEventBusSubjectMapping(t *testing.T) {
+	bus := &NATSEventBus{config: NATSConfig{SubjectPrefix: "events"}}
+
+	if got := bus.subject("user.created"); got != "events.user.created" {
+		t.Errorf("Expected events.user.created, got %s", got)
+	}
+	if got := bus.subject("bad topic*"); got != "events.bad_topic_" {
+		t.Errorf("Expected events.bad_topic_, got %s", got)
+	}
+	if got := durableName("svc", "user.created"); got != "svc_user_created" {
+		t.Errorf("Expected svc_user_created, got %s", got)
+	}
+}
@@ -0,0 +1,414 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// maxNotifyPayload is the largest payload Postgres accepts in NOTIFY,
+// minus some headroom for the envelope fields
+const maxNotifyPayload = 7900
+
+// PostgresBridgeConfig configures the LISTEN/NOTIFY event bridge
+type PostgresBridgeConfig struct {
+	Channel          string
+	Topics           []string
+	MaxNotifyPayload int
+	PayloadTTL       time.Duration
+	ReconnectWait    time.Duration
+	MaxReconnectWait time.Duration
+	DedupeSize       int
+}
+
+// EventPayload stores event payloads that are too large for NOTIFY.
+// Notifications reference a row by ID instead of carrying the event.
+type EventPayload struct {
+	ID        string    `gorm:"primaryKey;size:36"`
+	Data      []byte    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// TableName returns the table name for the EventPayload entity
+func (EventPayload) TableName() string {
+	return "event_payloads"
+}
+
+type payloadStore interface {
+	Save(ctx context.Context, id string, data []byte) error
+	Load(ctx context.Context, id string) ([]byte, error)
+	DeleteBefore(ctx context.Context, before time.Time) error
+}
+
+type notifier interface {
+	Notify(ctx context.Context, channel, payload string) error
+}
+
+type listener interface {
+	// Listen passes the notifications of channel to handle until the
+	// connection fails or ctx is done. connected reports whether LISTEN
+	// succeeded before that.
+	Listen(ctx context.Context, channel string, handle func(payload string)) (connected bool, err error)
+}
+
+// PostgresBridge forwards selected topics between service instances through
+// Postgres LISTEN/NOTIFY. Events published on the local bus are sent with
+// NOTIFY and notifications from other instances are published locally.
+type PostgresBridge struct {
+	config   PostgresBridgeConfig
+	bus      EventBus
+	codec    *eventCodec
+	logger   Logger
+	notifier notifier
+	listener listener
+	store    payloadStore
+	seen     *seenEvents
+	subs     []Subscription
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// DefaultPostgresBridgeConfig returns the default bridge configuration
+func DefaultPostgresBridgeConfig() PostgresBridgeConfig {
+	return PostgresBridgeConfig{
+		Channel:          "events",
+		MaxNotifyPayload: maxNotifyPayload,
+		PayloadTTL:       time.Hour,
+		ReconnectWait:    time.Second,
+		MaxReconnectWait: 30 * time.Second,
+		DedupeSize:       10000,
+	}
+}
+
+// NewPostgresBridge creates a bridge that reuses the connection settings of
+// a GORM Postgres database
+func NewPostgresBridge(db *gorm.DB, bus EventBus, cfg PostgresBridgeConfig) (*PostgresBridge, error) {
+	dialector, ok := db.Dialector.(*postgres.Dialector)
+	if !ok {
+		return nil, fmt.Errorf("postgres bridge requires a postgres database, got %s", db.Dialector.Name())
+	}
+
+	bridge := newPostgresBridge(bus, cfg, &gormNotifier{db: db}, &gormPayloadStore{db: db})
+	bridge.listener = &pgxListener{dsn: dialector.Config.DSN}
+
+	if err := db.AutoMigrate(&EventPayload{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate event payloads: %w", err)
+	}
+
+	return bridge, nil
+}
+
+func newPostgresBridge(bus EventBus, cfg PostgresBridgeConfig, n notifier, store payloadStore) *PostgresBridge {
+	defaults := DefaultPostgresBridgeConfig()
+	if cfg.Channel == "" {
+		cfg.Channel = defaults.Channel
+	}
+	if cfg.MaxNotifyPayload <= 0 || cfg.MaxNotifyPayload > maxNotifyPayload {
+		cfg.MaxNotifyPayload = defaults.MaxNotifyPayload
+	}
+	if cfg.PayloadTTL <= 0 {
+		cfg.PayloadTTL = defaults.PayloadTTL
+	}
+	if cfg.ReconnectWait <= 0 {
+		cfg.ReconnectWait = defaults.ReconnectWait
+	}
+	if cfg.MaxReconnectWait <= 0 {
+		cfg.MaxReconnectWait = defaults.MaxReconnectWait
+	}
+	if cfg.DedupeSize <= 0 {
+		cfg.DedupeSize = defaults.DedupeSize
+	}
+
+	return &PostgresBridge{
+		config:   cfg,
+		bus:      bus,
+		codec:    newEventCodec(),
+		logger:   busLogger(bus),
+		notifier: n,
+		store:    store,
+		seen:     newSeenEvents(cfg.DedupeSize),
+	}
+}
+
+// RegisterEventType registers the concrete type that events of a topic are decoded into
+func (b *PostgresBridge) RegisterEventType(topic string, factory EventFactory) {
+	b.codec.register(topic, factory)
+}
+
+// Start subscribes to the configured topics and begins listening for
+// notifications from other instances
+func (b *PostgresBridge) Start(ctx context.Context) error {
+	for _, topic := range b.config.Topics {
+		sub, err := b.bus.Subscribe(topic, b.forwarder(topic))
+		if err != nil {
+			b.unsubscribe()
+			return fmt.Errorf("failed to subscribe bridge to topic %s: %w", topic, err)
+		}
+		b.subs = append(b.subs, sub)
+	}
+
+	ctx, b.cancel = context.WithCancel(ctx)
+
+	b.wg.Add(2)
+	go b.listen(ctx)
+	go b.cleanup(ctx)
+
+	return nil
+}
+
+// Stop removes the forwarding subscriptions from the bus, stops listening
+// and waits for the background goroutines to exit
+func (b *PostgresBridge) Stop() {
+	b.unsubscribe()
+
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.wg.Wait()
+}
+
+func (b *PostgresBridge) unsubscribe() {
+	for _, sub := range b.subs {
+		if err := sub.Unsubscribe(); err != nil {
+			b.logger.LogWarn(context.Background(), "Failed to unsubscribe postgres event bridge", map[string]interface{}{
+				"topic": sub.Topic(),
+				"error": err.Error(),
+			})
+		}
+	}
+	b.subs = nil
+}
+
+// forwarder returns the local handler that sends events of topic to the
+// other instances
+func (b *PostgresBridge) forwarder(topic string) func(Event) {
+	return func(event Event) {
+		// Events received from other instances are published on the
+		// local bus too and must not be sent back
+		if !b.seen.add(event.GetID()) {
+			return
+		}
+
+		ctx := context.Background()
+		if err := b.forward(ctx, topic, event); err != nil {
+			b.logger.LogError(ctx, "Failed to forward event to postgres", err, map[string]interface{}{
+				"event_id": event.GetID(),
+				"topic":    topic,
+			})
+		}
+	}
+}
+
+func (b *PostgresBridge) forward(ctx context.Context, topic string, event Event) error {
+	env, err := b.codec.wrap(topic, event)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > b.config.MaxNotifyPayload {
+		ref := uuid.New().String()
+		if err := b.store.Save(ctx, ref, env.Payload); err != nil {
+			return fmt.Errorf("failed to store event payload: %w", err)
+		}
+
+		env.Payload = nil
+		env.Ref = ref
+		if data, err = json.Marshal(env); err != nil {
+			return err
+		}
+	}
+
+	return b.notifier.Notify(ctx, b.config.Channel, string(data))
+}
+
+// handleNotification publishes an event received from another instance on
+// the local bus
+func (b *PostgresBridge) handleNotification(ctx context.Context, payload string) error {
+	env, err := b.codec.unwrap([]byte(payload))
+	if err != nil {
+		return err
+	}
+
+	if !b.seen.add(env.ID) {
+		return nil
+	}
+
+	if env.Ref != "" {
+		data, err := b.store.Load(ctx, env.Ref)
+		if err != nil {
+			return fmt.Errorf("failed to load event payload %s: %w", env.Ref, err)
+		}
+		env.Payload = data
+	}
+
+	event, err := b.codec.event(env.Topic, env)
+	if err != nil {
+		return err
+	}
+
+	return b.bus.Publish(env.Topic, event)
+}
+
+// listen holds a dedicated LISTEN connection and reconnects with
+// exponential backoff whenever it is lost. The backoff starts over once a
+// connection was established again.
+func (b *PostgresBridge) listen(ctx context.Context) {
+	defer b.wg.Done()
+
+	handle := func(payload string) {
+		if err := b.handleNotification(ctx, payload); err != nil {
+			b.logger.LogError(ctx, "Failed to handle postgres event notification", err)
+		}
+	}
+
+	wait := b.config.ReconnectWait
+	for {
+		connected, err := b.listener.Listen(ctx, b.config.Channel, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			wait = b.config.ReconnectWait
+		}
+
+		fields := map[string]interface{}{"retry_in_ms": wait.Milliseconds()}
+		if err != nil {
+			fields["error"] = err.Error()
+		}
+		b.logger.LogWarn(ctx, "Postgres event bridge connection lost", fields)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+		if wait > b.config.MaxReconnectWait {
+			wait = b.config.MaxReconnectWait
+		}
+	}
+}
+
+// cleanup removes stored payloads once every instance had time to load them
+func (b *PostgresBridge) cleanup(ctx context.Context) {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.config.PayloadTTL / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := b.store.DeleteBefore(ctx, time.Now().Add(-b.config.PayloadTTL)); err != nil {
+				b.logger.LogError(ctx, "Failed to clean up event payloads", err)
+			}
+		}
+	}
+}
+
+type gormNotifier struct {
+	db *gorm.DB
+}
+
+func (n *gormNotifier) Notify(ctx context.Context, channel, payload string) error {
+	return n.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// pgxListener listens on a dedicated connection, since LISTEN does not
+// survive the connection pool
+type pgxListener struct {
+	dsn string
+}
+
+func (l *pgxListener) Listen(ctx context.Context, channel string, handle func(payload string)) (bool, error) {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return false, err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		handle(notification.Payload)
+	}
+}
+
+type gormPayloadStore struct {
+	db *gorm.DB
+}
+
+func (s *gormPayloadStore) Save(ctx context.Context, id string, data []byte) error {
+	return s.db.WithContext(ctx).Create(&EventPayload{ID: id, Data: data}).Error
+}
+
+func (s *gormPayloadStore) Load(ctx context.Context, id string) ([]byte, error) {
+	var payload EventPayload
+	if err := s.db.WithContext(ctx).First(&payload, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("event payload %s not found", id)
+		}
+		return nil, err
+	}
+	return payload.Data, nil
+}
+
+func (s *gormPayloadStore) DeleteBefore(ctx context.Context, before time.Time) error {
+	return s.db.WithContext(ctx).Where("created_at < ?", before).Delete(&EventPayload{}).Error
+}
+
+// seenEvents remembers the most recent event IDs up to a fixed capacity
+type seenEvents struct {
+	ids   map[string]struct{}
+	order []string
+	next  int
+	mu    sync.Mutex
+}
+
+func newSeenEvents(capacity int) *seenEvents {
+	return &seenEvents{
+		ids:   make(map[string]struct{}, capacity),
+		order: make([]string, capacity),
+	}
+}
+
+// add records id and reports whether it had not been seen before
+func (s *seenEvents) add(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+
+	if evicted := s.order[s.next]; evicted != "" {
+		delete(s.ids, evicted)
+	}
+	s.order[s.next] = id
+	s.next = (s.next + 1) % len(s.order)
+	s.ids[id] = struct{}{}
+
+	return true
+}
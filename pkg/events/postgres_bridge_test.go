@@ -0,0 +1,326 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNotifier delivers notifications to every bridge attached to it,
+// including the sender, like Postgres does for its own LISTEN connection
+type fakeNotifier struct {
+	bridges  []*PostgresBridge
+	payloads []string
+	mu       sync.Mutex
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, channel, payload string) error {
+	n.mu.Lock()
+	n.payloads = append(n.payloads, payload)
+	bridges := append([]*PostgresBridge(nil), n.bridges...)
+	n.mu.Unlock()
+
+	for _, bridge := range bridges {
+		if err := bridge.handleNotification(ctx, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type memoryPayloadStore struct {
+	data map[string][]byte
+	mu   sync.Mutex
+}
+
+func (s *memoryPayloadStore) Save(ctx context.Context, id string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[id] = data
+	return nil
+}
+
+func (s *memoryPayloadStore) Load(ctx context.Context, id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.data[id]
+	if !ok {
+		return nil, fmt.Errorf("event payload %s not found", id)
+	}
+	return data, nil
+}
+
+func (s *memoryPayloadStore) DeleteBefore(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(ctx context.Context, channel, payload string) error {
+	return fmt.Errorf("connection refused")
+}
+
+type listenResult struct {
+	connected bool
+	payloads  []string
+}
+
+// fakeListener plays one scripted result per Listen call and then blocks
+// until ctx is done
+type fakeListener struct {
+	results []listenResult
+	calls   int
+	mu      sync.Mutex
+}
+
+func (l *fakeListener) Listen(ctx context.Context, channel string, handle func(payload string)) (bool, error) {
+	l.mu.Lock()
+	call := l.calls
+	l.calls++
+	l.mu.Unlock()
+
+	if call >= len(l.results) {
+		<-ctx.Done()
+		return false, ctx.Err()
+	}
+	for _, payload := range l.results[call].payloads {
+		handle(payload)
+	}
+	return l.results[call].connected, fmt.Errorf("connection lost")
+}
+
+func (l *fakeListener) callCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.calls
+}
+
+type largeEvent struct {
+	*BaseEvent
+	Body string `json:"body"`
+}
+
+func newTestBridges(t *testing.T, topics ...string) (*fakeNotifier, *memoryPayloadStore, []EventBus) {
+	notifier := &fakeNotifier{}
+	store := &memoryPayloadStore{data: make(map[string][]byte)}
+
+	var buses []EventBus
+	for i := 0; i < 2; i++ {
+		bus := NewEventBus(nil)
+		bridge := newPostgresBridge(bus, PostgresBridgeConfig{Topics: topics}, notifier, store)
+		bridge.RegisterEventType("user.created", func() Event { return &UserCreatedEvent{} })
+		bridge.RegisterEventType("file.uploaded", func() Event { return &largeEvent{} })
+
+		for _, topic := range topics {
+			if _, err := bus.Subscribe(topic, bridge.forwarder(topic)); err != nil {
+				t.Fatalf("Error subscribing bridge: %v", err)
+			}
+		}
+
+		notifier.bridges = append(notifier.bridges, bridge)
+		buses = append(buses, bus)
+	}
+
+	return notifier, store, buses
+}
+
+func TestPostgresBridgeForwardsBetweenInstances(t *testing.T) {
+	notifier, _, buses := newTestBridges(t, "user.created")
+
+	var received []*UserCreatedEvent
+	buses[1].Subscribe("user.created", func(event Event) {
+		received = append(received, event.(*UserCreatedEvent))
+	})
+
+	event := &UserCreatedEvent{
+		BaseEvent: NewBaseEvent("user.created"),
+		UserID:    1,
+		Username:  "john_doe",
+	}
+	if err := buses[0].Publish("user.created", event); err != nil {
+		t.Fatalf("Error publishing event: %v", err)
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 event on the other instance, got %d", len(received))
+	}
+	if received[0].UserID != 1 || received[0].GetID() != event.GetID() {
+		t.Errorf("Received event data mismatch: %+v", received[0])
+	}
+	if len(notifier.payloads) != 1 {
+		t.Errorf("Expected the event to be notified once, got %d", len(notifier.payloads))
+	}
+}
+
+func TestPostgresBridgeDedupesByEventID(t *testing.T) {
+	notifier, _, buses := newTestBridges(t, "user.created")
+
+	count := 0
+	buses[1].Subscribe("user.created", func(event Event) {
+		count++
+	})
+
+	event := &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 1}
+	buses[0].Publish("user.created", event)
+
+	// Redeliver the same notification, as happens after a reconnect
+	for _, bridge := range notifier.bridges {
+		bridge.handleNotification(context.Background(), notifier.payloads[0])
+	}
+
+	if count != 1 {
+		t.Errorf("Expected duplicate notifications to be ignored, got %d deliveries", count)
+	}
+}
+
+func TestPostgresBridgeIgnoresUnselectedTopics(t *testing.T) {
+	notifier, _, buses := newTestBridges(t, "user.created")
+
+	buses[0].Publish("user.deleted", NewBaseEvent("user.deleted"))
+
+	if len(notifier.payloads) != 0 {
+		t.Errorf("Expected unselected topic not to be forwarded, got %d notifications", len(notifier.payloads))
+	}
+}
+
+func TestPostgresBridgeLargePayloadByReference(t *testing.T) {
+	notifier, store, buses := newTestBridges(t, "file.uploaded")
+
+	var received *largeEvent
+	buses[1].Subscribe("file.uploaded", func(event Event) {
+		received = event.(*largeEvent)
+	})
+
+	body := strings.Repeat("x", 2*maxNotifyPayload)
+	buses[0].Publish("file.uploaded", &largeEvent{BaseEvent: NewBaseEvent("file.uploaded"), Body: body})
+
+	if len(store.data) != 1 {
+		t.Fatalf("Expected payload to be stored, got %d rows", len(store.data))
+	}
+	if len(notifier.payloads[0]) > maxNotifyPayload {
+		t.Errorf("Notification payload exceeds limit: %d bytes", len(notifier.payloads[0]))
+	}
+	if received == nil || received.Body != body {
+		t.Error("Other instance didn't receive the stored payload")
+	}
+}
+
+func TestPostgresBridgeLogsForwardFailuresToBusLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	bus := NewEventBus(&EventBusConfig{Logger: logger})
+	bridge := newPostgresBridge(bus, PostgresBridgeConfig{Topics: []string{"user.created"}}, failingNotifier{}, nil)
+	bus.Subscribe("user.created", bridge.forwarder("user.created"))
+
+	bus.Publish("user.created", &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 1})
+
+	if _, errs := logger.counts(); errs != 1 {
+		t.Errorf("Expected the forward failure on the bus logger, got %d errors", errs)
+	}
+}
+
+func TestPostgresBridgeBackoffResetsAfterReconnect(t *testing.T) {
+	logger := &recordingLogger{}
+	bus := NewEventBus(&EventBusConfig{Logger: logger})
+	bridge := newPostgresBridge(bus, PostgresBridgeConfig{
+		ReconnectWait:    time.Millisecond,
+		MaxReconnectWait: 4 * time.Millisecond,
+	}, &fakeNotifier{}, &memoryPayloadStore{data: make(map[string][]byte)})
+	listener := &fakeListener{results: []listenResult{
+		{}, {}, {}, {},
+		{connected: true},
+		{},
+	}}
+	bridge.listener = listener
+
+	if err := bridge.Start(context.Background()); err != nil {
+		t.Fatalf("Error starting bridge: %v", err)
+	}
+	if !waitFor(t, func() bool { return listener.callCount() > len(listener.results) }) {
+		t.Fatalf("Expected the bridge to keep reconnecting, got %d attempts", listener.callCount())
+	}
+	bridge.Stop()
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	var waits []int64
+	for _, fields := range logger.warnings {
+		waits = append(waits, fields["retry_in_ms"].(int64))
+	}
+	if fmt.Sprint(waits) != "[1 2 4 4 1 2]" {
+		t.Errorf("Expected the backoff to grow, cap and start over after a reconnect, got %v", waits)
+	}
+}
+
+func TestPostgresBridgePublishesNotifications(t *testing.T) {
+	// A notifier without bridges only records the payload
+	captured := &fakeNotifier{}
+	sender := newPostgresBridge(NewEventBus(nil), PostgresBridgeConfig{}, captured, nil)
+	event := &UserCreatedEvent{BaseEvent: NewBaseEvent("user.created"), UserID: 3}
+	if err := sender.forward(context.Background(), "user.created", event); err != nil {
+		t.Fatalf("Error forwarding event: %v", err)
+	}
+
+	bus := NewEventBus(nil)
+	received := make(chan *UserCreatedEvent, 1)
+	bus.Subscribe("user.created", func(event Event) {
+		received <- event.(*UserCreatedEvent)
+	})
+
+	receiver := newPostgresBridge(bus, PostgresBridgeConfig{}, &fakeNotifier{}, nil)
+	receiver.RegisterEventType("user.created", func() Event { return &UserCreatedEvent{} })
+	receiver.listener = &fakeListener{results: []listenResult{{connected: true, payloads: captured.payloads}}}
+
+	if err := receiver.Start(context.Background()); err != nil {
+		t.Fatalf("Error starting bridge: %v", err)
+	}
+	defer receiver.Stop()
+
+	select {
+	case got := <-received:
+		if got.UserID != 3 || got.GetID() != event.GetID() {
+			t.Errorf("Received event data mismatch: %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Notification was not published on the local bus")
+	}
+}
+
+func TestPostgresBridgeStopUnsubscribes(t *testing.T) {
+	bus := NewEventBus(&EventBusConfig{Logger: &recordingLogger{}})
+	bridge := newPostgresBridge(bus, PostgresBridgeConfig{Topics: []string{"user.created", "user.deleted"}}, &fakeNotifier{}, &memoryPayloadStore{data: make(map[string][]byte)})
+	bridge.listener = &fakeListener{}
+
+	if err := bridge.Start(context.Background()); err != nil {
+		t.Fatalf("Error starting bridge: %v", err)
+	}
+	if !bus.HasCallback("user.created") || !bus.HasCallback("user.deleted") {
+		t.Fatal("Expected the bridge to subscribe to its topics")
+	}
+
+	bridge.Stop()
+	if bus.HasCallback("user.created") || bus.HasCallback("user.deleted") {
+		t.Error("Expected Stop to remove the forwarding subscriptions")
+	}
+	if got := len(bus.Inspect().Topics); got != 0 {
+		t.Errorf("Expected no topics left on the bus, got %d", got)
+	}
+}
+
+func TestSeenEventsEviction(t *testing.T) {
+	seen := newSeenEvents(2)
+
+	if !seen.add("a") || !seen.add("b") {
+		t.Fatal("Expected new IDs to be added")
+	}
+	if seen.add("a") {
+		t.Error("Expected duplicate ID to be rejected")
+	}
+
+	seen.add("c")
+	if !seen.add("a") {
+		t.Error("Expected oldest ID to be evicted")
+	}
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+)
+
+// retention keeps the last N events of retained topics so that late
+// subscribers can catch up on the current state
+type retention struct {
+	topics map[string]*retainedTopic
+	mu     sync.RWMutex
+}
+
+type retainedTopic struct {
+	limit  int
+	events []Event
+}
+
+func newRetention(limits map[string]int) *retention {
+	r := &retention{
+		topics: make(map[string]*retainedTopic),
+	}
+	for topic, limit := range limits {
+		r.retain(topic, limit)
+	}
+	return r
+}
+
+// retain sets the number of events kept for topic. A limit of zero stops
+// retaining the topic and discards its events.
+func (r *retention) retain(topic string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("retention limit must not be negative, got %d", limit)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit == 0 {
+		delete(r.topics, topic)
+		return nil
+	}
+
+	rt, ok := r.topics[topic]
+	if !ok {
+		r.topics[topic] = &retainedTopic{limit: limit}
+		return nil
+	}
+
+	rt.limit = limit
+	if len(rt.events) > limit {
+		rt.events = append([]Event(nil), rt.events[len(rt.events)-limit:]...)
+	}
+	return nil
+}
+
+// record stores event if its topic is retained
+func (r *retention) record(topic string, event Event) {
+	if event == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rt, ok := r.topics[topic]
+	if !ok {
+		return
+	}
+
+	rt.events = append(rt.events, event)
+	if len(rt.events) > rt.limit {
+		rt.events = append([]Event(nil), rt.events[len(rt.events)-rt.limit:]...)
+	}
+}
+
+// recordArgs stores the first publish argument if it is an Event
+func (r *retention) recordArgs(topic string, args ...interface{}) {
+	if len(args) == 0 {
+		return
+	}
+	if event, ok := args[0].(Event); ok {
+		r.record(topic, event)
+	}
+}
+
+// snapshot returns the retained events of topic, oldest first
+func (r *retention) snapshot(topic string) []Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rt, ok := r.topics[topic]
+	if !ok || len(rt.events) == 0 {
+		return nil
+	}
+	return append([]Event(nil), rt.events...)
+}
+
+func (r *retention) last(topic string) (Event, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rt, ok := r.topics[topic]
+	if !ok || len(rt.events) == 0 {
+		return nil, false
+	}
+	return rt.events[len(rt.events)-1], true
+}
+
+// counts returns the number of retained events per retained topic
+func (r *retention) counts() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int, len(r.topics))
+	for topic, rt := range r.topics {
+		counts[topic] = len(rt.events)
+	}
+	return counts
+}
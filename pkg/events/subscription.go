@@ -0,0 +1,46 @@
+package events
+
+import (
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// Subscription is the handle returned for every registered subscriber.
+// It stays valid after the subscriber is removed.
+type Subscription interface {
+	// ID uniquely identifies the subscription within its bus
+	ID() string
+	// Topic returns the topic the subscription listens on
+	Topic() string
+	// Unsubscribe removes the subscription. Deliveries that already started
+	// complete, but no new delivery begins once it returns.
+	Unsubscribe() error
+	// Stats returns the delivery counters of the subscription
+	Stats() SubscriptionStats
+}
+
+// SubscriptionStats is a snapshot of the counters kept per subscription
+type SubscriptionStats struct {
+	Delivered uint64 `json:"delivered"`
+	Dropped   uint64 `json:"dropped"`
+	Errors    uint64 `json:"errors"`
+}
+
+type subscriptionStats struct {
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	errors    atomic.Uint64
+}
+
+func (s *subscriptionStats) snapshot() SubscriptionStats {
+	return SubscriptionStats{
+		Delivered: s.delivered.Load(),
+		Dropped:   s.dropped.Load(),
+		Errors:    s.errors.Load(),
+	}
+}
+
+func newSubscriptionID() string {
+	return uuid.New().String()
+}
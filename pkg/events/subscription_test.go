@@ -0,0 +1,216 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionUnsubscribeClosure(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var first, second int
+	newHandler := func(count *int) func(Event) {
+		return func(event Event) { *count++ }
+	}
+
+	sub, err := eventBus.Subscribe("test.event", newHandler(&first))
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+	eventBus.Subscribe("test.event", newHandler(&second))
+
+	if sub.Topic() != "test.event" || sub.ID() == "" {
+		t.Errorf("Unexpected subscription: topic %q, id %q", sub.Topic(), sub.ID())
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Error unsubscribing: %v", err)
+	}
+	eventBus.Publish("test.event", NewBaseEvent("test.event"))
+
+	if first != 0 || second != 1 {
+		t.Errorf("Expected only the remaining closure to be called, got %d and %d", first, second)
+	}
+	if err := sub.Unsubscribe(); err == nil {
+		t.Error("Expected error when unsubscribing twice")
+	}
+}
+
+func TestUnsubscribeByID(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	sub, _ := eventBus.Subscribe("test.event", func(event Event) {})
+	if err := eventBus.Unsubscribe(sub.ID()); err != nil {
+		t.Fatalf("Error unsubscribing by ID: %v", err)
+	}
+	if eventBus.HasCallback("test.event") {
+		t.Error("Should not have callback after unsubscribe")
+	}
+	if err := eventBus.Unsubscribe("unknown"); err == nil {
+		t.Error("Expected error for unknown subscription ID")
+	}
+}
+
+func TestSubscriptionStats(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	sub, _ := eventBus.Subscribe("test.event", func(event Event) {})
+	for i := 0; i < 3; i++ {
+		eventBus.Publish("test.event", NewBaseEvent("test.event"))
+	}
+
+	if stats := sub.Stats(); stats.Delivered != 3 {
+		t.Errorf("Expected 3 deliveries, got %+v", stats)
+	}
+}
+
+func TestUnsubscribeFromHandler(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var calls int
+	var sub Subscription
+	sub, _ = eventBus.Subscribe("test.event", func(event Event) {
+		calls++
+		sub.Unsubscribe()
+	})
+
+	eventBus.Publish("test.event", NewBaseEvent("test.event"))
+	eventBus.Publish("test.event", NewBaseEvent("test.event"))
+
+	if calls != 1 {
+		t.Errorf("Expected a single call, got %d", calls)
+	}
+}
+
+func TestUnsubscribeDuringPublish(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var calls atomic.Int64
+	subs := make([]Subscription, 50)
+	for i := range subs {
+		subs[i], _ = eventBus.SubscribeAsync("test.event", func(event Event) {
+			calls.Add(1)
+		}, false)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			eventBus.Publish("test.event", NewBaseEvent("test.event"))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}()
+	wg.Wait()
+	eventBus.WaitAsync()
+
+	if eventBus.HasCallback("test.event") {
+		t.Error("Expected every subscription to be removed")
+	}
+
+	before := calls.Load()
+	eventBus.Publish("test.event", NewBaseEvent("test.event"))
+	eventBus.WaitAsync()
+	if calls.Load() != before {
+		t.Error("Expected no deliveries after unsubscribe")
+	}
+}
+
+func TestSubscribeOnceConcurrentPublish(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var calls atomic.Int64
+	eventBus.SubscribeOnceAsync("test.event", func(event Event) {
+		calls.Add(1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			eventBus.Publish("test.event", NewBaseEvent("test.event"))
+		}()
+	}
+	wg.Wait()
+	eventBus.WaitAsync()
+
+	if calls.Load() != 1 {
+		t.Errorf("Expected once handler to run once, got %d", calls.Load())
+	}
+}
+
+func TestBatchSubscriptionUnsubscribeFlushes(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	defer eventBus.Close()
+
+	recorder := &batchRecorder{}
+	sub, err := eventBus.SubscribeBatch("index.user", 100, time.Hour, recorder.handle)
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	eventBus.Publish("index.user", NewBaseEvent("index.user"))
+	sub.Unsubscribe()
+
+	if got := recorder.sizes(); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected pending events to be flushed on unsubscribe, got %v", got)
+	}
+	if stats := sub.Stats(); stats.Delivered != 2 {
+		t.Errorf("Expected 2 deliveries, got %+v", stats)
+	}
+}
+
+func TestChannelSubscriptionHandle(t *testing.T) {
+	channelEventBus := NewChannelEventBus(nil)
+	defer channelEventBus.Close()
+
+	var sub Subscription = channelEventBus.SubscribeChannel("user.created", 1)
+	channelEventBus.PublishEvent(context.Background(), NewBaseEvent("user.created"))
+	channelEventBus.PublishEvent(context.Background(), NewBaseEvent("user.created"))
+
+	if stats := sub.Stats(); stats.Delivered != 1 || stats.Dropped != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if err := channelEventBus.Unsubscribe(sub.ID()); err != nil {
+		t.Fatalf("Error unsubscribing by ID: %v", err)
+	}
+	if !sub.(*ChannelSubscriber).IsClosed() {
+		t.Error("Expected channel subscriber to be closed")
+	}
+}
+
+func TestUnsubscribeHandlerByTopicAndFunc(t *testing.T) {
+	eventBus := NewEventBus(nil)
+
+	var removed, kept int
+	remove := func(event Event) { removed++ }
+	eventBus.Subscribe("test.event", remove)
+	eventBus.Subscribe("test.event", func(event Event) { kept++ })
+
+	if err := eventBus.UnsubscribeHandler("test.event", remove); err != nil {
+		t.Fatalf("Error unsubscribing: %v", err)
+	}
+	eventBus.Publish("test.event", NewBaseEvent("test.event"))
+
+	if removed != 0 || kept != 1 {
+		t.Errorf("Expected only the remaining handler to be called, got %d and %d", removed, kept)
+	}
+	if err := eventBus.UnsubscribeHandler("test.event", remove); err == nil {
+		t.Error("Expected error when the handler is no longer subscribed")
+	}
+	if err := eventBus.UnsubscribeHandler("missing", remove); err == nil {
+		t.Error("Expected error for an unknown topic")
+	}
+}
@@ -0,0 +1,177 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+// DeadLetterTopic receives a DeadLetterEvent for every handler invocation
+// that failed, such as a handler exceeding its deadline
+const DeadLetterTopic = "events.dead_letter"
+
+// ErrHandlerTimeout is reported when a handler exceeds its deadline
+var ErrHandlerTimeout = errors.New("event handler timed out")
+
+// DeadLetterEvent describes a failed delivery
+type DeadLetterEvent struct {
+	*BaseEvent
+	Topic          string `json:"topic"`
+	SubscriptionID string `json:"subscription_id"`
+	Handler        string `json:"handler"`
+	Event          Event  `json:"event"`
+	Error          string `json:"error"`
+	Err            error  `json:"-"`
+}
+
+// SubscribeOption configures a handler subscription
+type SubscribeOption func(*eventHandler)
+
+// WithTimeout sets the deadline of every invocation of the handler,
+// overriding EventBusConfig.DefaultTimeout.
+//
+// Go cannot stop a running goroutine, so the deadline only stops waiting
+// for the handler. A handler that misses it is abandoned: it keeps running,
+// and keeps whatever it holds, until it returns on its own. Handlers should
+// take a context.Context and return once it is cancelled. Metrics.Abandoned
+// counts the handlers still running past their deadline, and events they
+// publish after the bus is closed are rejected.
+func WithTimeout(timeout time.Duration) SubscribeOption {
+	return func(h *eventHandler) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+// WithSlowThreshold sets the duration after which a handler that still
+// finishes in time is reported as slow, overriding
+// EventBusConfig.SlowHandlerThreshold
+func WithSlowThreshold(threshold time.Duration) SubscribeOption {
+	return func(h *eventHandler) {
+		if threshold > 0 {
+			h.slowThreshold = threshold
+		}
+	}
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// acceptsContext reports whether fn takes a context.Context as its first parameter
+func acceptsContext(fn reflect.Type) bool {
+	return fn.NumIn() > 0 && fn.In(0) == contextType
+}
+
+// invoke calls the handler under its deadline and reports whether it
+// finished in time. A handler that misses the deadline has its context
+// cancelled and is abandoned: the publisher moves on while the handler
+// goroutine finishes on its own, counted in Metrics.Abandoned until then.
+// Batch subscriptions only queue the event here; their handler gets the
+// deadline instead.
+func (bus *eventBus) invoke(handler *eventHandler, args ...interface{}) bool {
+	timeout := handler.timeout
+	if timeout <= 0 {
+		timeout = bus.config.DefaultTimeout
+	}
+	if timeout <= 0 || handler.batcher != nil {
+		handler.callBack.Call(bus.setUpPublish(context.Background(), handler, args...))
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Panics are handed back so that they surface on the publisher as
+	// they would without a deadline
+	done := make(chan interface{}, 1)
+	start := time.Now()
+	go func() {
+		defer func() { done <- recover() }()
+		handler.callBack.Call(bus.setUpPublish(ctx, handler, args...))
+	}()
+
+	select {
+	case p := <-done:
+		if p != nil {
+			panic(p)
+		}
+		bus.checkSlow(handler, time.Since(start))
+		return true
+	case <-ctx.Done():
+		cancel()
+		bus.abandon(handler, done)
+		bus.handlerTimedOut(handler, timeout, args...)
+		return false
+	}
+}
+
+// abandon counts a handler that missed its deadline until it returns. A
+// panic can no longer reach the publisher, so it is logged instead.
+func (bus *eventBus) abandon(handler *eventHandler, done <-chan interface{}) {
+	bus.metrics.abandoned.Add(1)
+	go func() {
+		defer bus.metrics.abandoned.Add(-1)
+		if p := <-done; p != nil {
+			bus.logger.LogError(context.Background(), "Abandoned event handler panicked", fmt.Errorf("%v", p), handler.logFields())
+		}
+	}()
+}
+
+func (bus *eventBus) checkSlow(handler *eventHandler, elapsed time.Duration) {
+	threshold := handler.slowThreshold
+	if threshold <= 0 {
+		threshold = bus.config.SlowHandlerThreshold
+	}
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	fields := handler.logFields()
+	fields["duration_ms"] = elapsed.Milliseconds()
+	fields["threshold_ms"] = threshold.Milliseconds()
+
+	bus.logger.LogWarn(context.Background(), "Slow event handler", fields)
+}
+
+func (bus *eventBus) handlerTimedOut(handler *eventHandler, timeout time.Duration, args ...interface{}) {
+	bus.metrics.errors.Add(1)
+	handler.stats.errors.Add(1)
+
+	err := fmt.Errorf("%w after %s", ErrHandlerTimeout, timeout)
+	fields := handler.logFields()
+	fields["timeout_ms"] = timeout.Milliseconds()
+
+	bus.logger.LogError(context.Background(), "Event handler timed out", err, fields)
+
+	// Failures of dead-letter handlers are not dead-lettered again
+	if handler.topic == DeadLetterTopic {
+		return
+	}
+
+	event, _ := eventArg(args...)
+	bus.PublishAsync(DeadLetterTopic, &DeadLetterEvent{
+		BaseEvent:      NewBaseEvent(DeadLetterTopic),
+		Topic:          handler.topic,
+		SubscriptionID: handler.id,
+		Handler:        fields["handler"].(string),
+		Event:          event,
+		Error:          err.Error(),
+		Err:            err,
+	})
+}
+
+func (h *eventHandler) logFields() map[string]interface{} {
+	name := "unknown"
+	if fn := runtime.FuncForPC(h.callBack.Pointer()); fn != nil {
+		name = fn.Name()
+	}
+
+	return map[string]interface{}{
+		"handler":         name,
+		"topic":           h.topic,
+		"subscription_id": h.id,
+	}
+}
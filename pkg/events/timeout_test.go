@@ -0,0 +1,250 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	warnings []map[string]interface{}
+	errors   []error
+}
+
+func (l *recordingLogger) LogWarn(ctx context.Context, message string, fields ...map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fields[0])
+}
+
+func (l *recordingLogger) LogError(ctx context.Context, message string, err error, fields ...map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errors = append(l.errors, err)
+}
+
+func (l *recordingLogger) counts() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.warnings), len(l.errors)
+}
+
+func TestHandlerTimeoutCancelsContext(t *testing.T) {
+	logger := &recordingLogger{}
+	eventBus := NewEventBus(&EventBusConfig{DefaultTimeout: 50 * time.Millisecond, Logger: logger})
+
+	deadLetters := make(chan *DeadLetterEvent, 1)
+	eventBus.Subscribe(DeadLetterTopic, func(event Event) {
+		deadLetters <- event.(*DeadLetterEvent)
+	})
+
+	cancelled := make(chan struct{})
+	sub, _ := eventBus.Subscribe("slow.task", func(ctx context.Context, event Event) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	event := NewBaseEvent("slow.task")
+	start := time.Now()
+	eventBus.Publish("slow.task", event)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Publish should return once the deadline passes, took %s", elapsed)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Handler context was not cancelled")
+	}
+
+	select {
+	case dl := <-deadLetters:
+		if !errors.Is(dl.Err, ErrHandlerTimeout) || dl.Topic != "slow.task" || dl.SubscriptionID != sub.ID() || dl.Event != event {
+			t.Errorf("Unexpected dead letter: %+v", dl)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timeout was not sent to the dead-letter topic")
+	}
+
+	if stats := sub.Stats(); stats.Errors != 1 || stats.Delivered != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if _, errs := logger.counts(); errs != 1 {
+		t.Errorf("Expected the timeout to be logged, got %d errors", errs)
+	}
+}
+
+func TestSubscriptionTimeoutOverridesDefault(t *testing.T) {
+	eventBus := NewEventBus(&EventBusConfig{DefaultTimeout: time.Hour, Logger: &recordingLogger{}})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	sub, _ := eventBus.Subscribe("slow.task", func(event Event) {
+		<-release
+	}, WithTimeout(20*time.Millisecond))
+
+	done := make(chan struct{})
+	go func() {
+		eventBus.Publish("slow.task", NewBaseEvent("slow.task"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Handler without context should be abandoned after its deadline")
+	}
+	if stats := sub.Stats(); stats.Errors != 1 {
+		t.Errorf("Expected a timeout error, got %+v", stats)
+	}
+}
+
+func TestSlowHandlerWarning(t *testing.T) {
+	logger := &recordingLogger{}
+	eventBus := NewEventBus(&EventBusConfig{
+		DefaultTimeout:       time.Second,
+		SlowHandlerThreshold: 10 * time.Millisecond,
+		Logger:               logger,
+	})
+
+	eventBus.Subscribe("task", func(event Event) {
+		time.Sleep(30 * time.Millisecond)
+	})
+	eventBus.Subscribe("task", func(event Event) {})
+
+	eventBus.Publish("task", NewBaseEvent("task"))
+
+	warnings, errs := logger.counts()
+	if warnings != 1 || errs != 0 {
+		t.Fatalf("Expected one slow handler warning, got %d warnings and %d errors", warnings, errs)
+	}
+	if logger.warnings[0]["topic"] != "task" || logger.warnings[0]["handler"] == "" {
+		t.Errorf("Expected the warning to identify the handler, got %v", logger.warnings[0])
+	}
+}
+
+func TestHandlerPanicWithDeadline(t *testing.T) {
+	eventBus := NewEventBus(nil)
+	eventBus.Subscribe("task", func(event Event) {
+		panic("boom")
+	})
+
+	defer func() {
+		if recover() != "boom" {
+			t.Error("Expected the handler panic to reach the publisher")
+		}
+	}()
+	eventBus.Publish("task", NewBaseEvent("task"))
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestAbandonedHandlerKeepsRunningAndIsCounted(t *testing.T) {
+	eventBus := NewEventBus(&EventBusConfig{DefaultTimeout: 20 * time.Millisecond, Logger: &recordingLogger{}})
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	eventBus.Subscribe("slow.task", func(event Event) {
+		<-release
+		close(finished)
+	})
+
+	eventBus.Publish("slow.task", NewBaseEvent("slow.task"))
+	eventBus.Publish("slow.task", NewBaseEvent("slow.task"))
+	if got := eventBus.Metrics().Abandoned; got != 2 {
+		t.Fatalf("Expected 2 abandoned handlers, got %d", got)
+	}
+
+	select {
+	case <-finished:
+		t.Fatal("The deadline should not stop the handler")
+	default:
+	}
+
+	close(release)
+	if !waitFor(t, func() bool { return eventBus.Metrics().Abandoned == 0 }) {
+		t.Errorf("Expected the count to drop once the handlers return, got %d", eventBus.Metrics().Abandoned)
+	}
+}
+
+func TestAbandonedHandlerCannotPublishAfterClose(t *testing.T) {
+	eventBus := NewEventBus(&EventBusConfig{DefaultTimeout: 20 * time.Millisecond, Logger: &recordingLogger{}})
+
+	release := make(chan struct{})
+	published := make(chan error, 1)
+	eventBus.Subscribe("slow.task", func(event Event) {
+		<-release
+		published <- eventBus.Publish("late.result", NewBaseEvent("late.result"))
+	})
+
+	eventBus.Publish("slow.task", NewBaseEvent("slow.task"))
+	if err := eventBus.Close(); err != nil {
+		t.Fatalf("Error closing bus: %v", err)
+	}
+	if got := eventBus.Metrics().Abandoned; got != 1 {
+		t.Errorf("Expected Close not to wait for the abandoned handler, got %d abandoned", got)
+	}
+
+	close(release)
+	select {
+	case err := <-published:
+		if err == nil {
+			t.Error("Expected a publish after Close to be rejected")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Abandoned handler did not finish")
+	}
+}
+
+func TestAbandonedHandlerPanicIsLogged(t *testing.T) {
+	logger := &recordingLogger{}
+	eventBus := NewEventBus(&EventBusConfig{DefaultTimeout: 20 * time.Millisecond, Logger: logger})
+
+	release := make(chan struct{})
+	eventBus.Subscribe("slow.task", func(event Event) {
+		<-release
+		panic("late boom")
+	})
+
+	eventBus.Publish("slow.task", NewBaseEvent("slow.task"))
+	close(release)
+
+	// One error for the timeout, one for the panic
+	if !waitFor(t, func() bool { _, errs := logger.counts(); return errs == 2 }) {
+		_, errs := logger.counts()
+		t.Errorf("Expected the late panic to be logged, got %d errors", errs)
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	bus := NewEventBus(&EventBusConfig{}).(*eventBus)
+	if _, ok := bus.logger.(zerologLogger); !ok {
+		t.Errorf("Expected the zerolog logger without a configured one, got %T", bus.logger)
+	}
+}
+
+func TestDefaultBufferSize(t *testing.T) {
+	channelEventBus := NewChannelEventBus(&EventBusConfig{DefaultBufferSize: 3})
+	defer channelEventBus.Close()
+
+	subscriber := channelEventBus.SubscribeChannel("user.created", 0)
+	if got := cap(subscriber.channel); got != 3 {
+		t.Errorf("Expected the configured buffer size, got %d", got)
+	}
+}